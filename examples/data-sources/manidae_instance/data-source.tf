@@ -0,0 +1,10 @@
+data "manidae_instance" "this" {
+  # Actions beyond the built-in lifecycle the template handles itself.
+  custom_actions = ["snapshot"]
+}
+
+resource "terraform_data" "bootstrap" {
+  count = data.manidae_instance.this.is_create ? 1 : 0
+
+  input = data.manidae_instance.this.id
+}
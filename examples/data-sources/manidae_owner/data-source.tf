@@ -0,0 +1,14 @@
+data "manidae_owner" "this" {}
+
+locals {
+  owner_is_admin = contains(coalesce(data.manidae_owner.this.roles, []), "admin")
+}
+
+resource "terraform_data" "user" {
+  input = {
+    username       = data.manidae_owner.this.username
+    full_name      = data.manidae_owner.this.full_name
+    ssh_public_key = data.manidae_owner.this.ssh_public_key
+    admin          = local.owner_is_admin
+  }
+}
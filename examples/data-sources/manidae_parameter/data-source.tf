@@ -2,11 +2,55 @@ data "manidae_parameter" "root_volume_size_gb" {
   name         = "root_volume_size_gb"
   display_name = "Root Volume Size (GB)"
   description  = "How large should the root volume for the instance be?"
+  group        = "Storage"
+  icon         = "/icon/database.svg"
   default      = 30
   type         = "number"
 
   validation {
-    min = 20
+    min       = 20
+    step      = 10
+    monotonic = "increasing"
+  }
+}
+
+data "manidae_parameter" "cpu_count" {
+  name         = "cpu_count"
+  display_name = "CPU count"
+  group        = "Compute"
+  order        = 1
+  form_type    = "radio"
+  default      = 4
+
+  option {
+    name  = "2 vCPU"
+    value = "2"
+  }
+  option {
+    name  = "4 vCPU"
+    value = "4"
+  }
+  option {
+    name  = "8 vCPU"
+    value = "8"
+  }
+  option {
+    name  = "16 vCPU"
+    value = "16"
+  }
+}
+
+data "manidae_parameter" "idle_timeout" {
+  name         = "idle_timeout"
+  display_name = "Idle timeout"
+  description  = "Stop the instance after it has been idle for this long, e.g. 30m or 2h."
+  type         = "duration"
+  unit         = "m"
+  default      = "30m"
+
+  validation {
+    min = "5m"
+    max = "24h"
   }
 }
 
@@ -17,8 +61,9 @@ data "manidae_parameter" "instance_type" {
   default      = "SA2.MEDIUM8"
 
   option {
-    name  = "2 vCPU, 2 GiB RAM"
-    value = "SA2.MEDIUM2"
+    name       = "2 vCPU, 2 GiB RAM"
+    value      = "SA2.MEDIUM2"
+    deprecated = "Use SA2.MEDIUM4 instead."
   }
   option {
     name  = "2 vCPU, 4 GiB RAM"
@@ -37,8 +82,10 @@ data "manidae_parameter" "instance_type" {
     value = "SA2.LARGE8"
   }
   option {
-    name  = "4 vCPU, 16 GiB RAM"
-    value = "SA2.LARGE16"
+    name        = "4 vCPU, 16 GiB RAM"
+    value       = "SA2.LARGE16"
+    description = "Sold out in this region."
+    disabled    = true
   }
 }
 
@@ -48,5 +95,74 @@ data "manidae_parameter" "docker_image" {
   description  = "The docker image to create container"
   default      = "codercom/enterprise-base:ubuntu"
   type         = "string"
+
+  validation {
+    regex      = "^[a-z0-9./_-]+(:[A-Za-z0-9._-]+)?$"
+    max_length = 255
+    error      = "{name} must be a docker image reference, got {value}"
+  }
+}
+
+data "manidae_parameter" "enable_gpu" {
+  name         = "enable_gpu"
+  display_name = "Enable GPU"
+  description  = "Attach a GPU to the instance?"
+  default      = false
+}
+
+data "manidae_parameter" "gpu_model" {
+  name         = "gpu_model"
+  display_name = "GPU model"
+  default      = "nvidia-t4"
+
+  option {
+    value = "nvidia-t4"
+  }
+  option {
+    value = "nvidia-l4"
+  }
+
+  visible_when {
+    parameter = data.manidae_parameter.enable_gpu.name
+    values    = ["true"]
+  }
+}
+
+data "manidae_parameter" "extra_labels" {
+  name         = "extra_labels"
+  display_name = "Extra labels"
+  description  = "Labels added to every resource, as a JSON object of strings."
+  type         = "map(string)"
+  default      = { team = "platform" }
+
+  validation {
+    json_schema = jsonencode({
+      propertyNames = { pattern = "^[a-z][a-z0-9_-]*$" }
+    })
+  }
 }
 
+data "manidae_parameter" "ide_plugins" {
+  name         = "ide_plugins"
+  display_name = "IDE plugins"
+  description  = "Which IDE plugins should be installed?"
+  type         = "list(string)"
+  default      = ["go"]
+
+  option {
+    name  = "Go"
+    value = "go"
+  }
+  option {
+    name  = "Python"
+    value = "python"
+  }
+  option {
+    name  = "Rust"
+    value = "rust"
+  }
+
+  validation {
+    max_items = 2
+  }
+}
@@ -0,0 +1,33 @@
+data "manidae_preset" "small" {
+  name         = "small"
+  display_name = "Small"
+  description  = "2 vCPU and a 30 GB root volume."
+
+  parameters = {
+    cpu_count           = 2
+    root_volume_size_gb = 30
+  }
+}
+
+data "manidae_preset" "large" {
+  name         = "large"
+  display_name = "Large"
+  description  = "8 vCPU and a 100 GB root volume."
+
+  parameters = {
+    cpu_count           = 8
+    root_volume_size_gb = 100
+  }
+}
+
+data "manidae_parameter" "cpu_count" {
+  name    = "cpu_count"
+  default = 4
+  presets = [data.manidae_preset.small.id, data.manidae_preset.large.id]
+}
+
+data "manidae_parameter" "root_volume_size_gb" {
+  name    = "root_volume_size_gb"
+  default = 50
+  presets = [data.manidae_preset.small.id, data.manidae_preset.large.id]
+}
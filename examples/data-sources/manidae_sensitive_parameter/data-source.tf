@@ -0,0 +1,10 @@
+data "manidae_sensitive_parameter" "api_key" {
+  name         = "api_key"
+  display_name = "API key"
+  description  = "Token the workspace uses to reach the package registry."
+  type         = "string"
+
+  validation {
+    min_length = 32
+  }
+}
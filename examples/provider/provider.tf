@@ -1,3 +1,18 @@
 provider "manidae" {
-  # example configuration here
+  # Optional: fetch instance and owner context from the Manidae API.
+  # The token defaults to the MANIDAE_TOKEN environment variable.
+  endpoint = "https://manidae.example.com"
+
+  # Optional: bulk parameter values keyed by parameter name.
+  # Defaults to the MANIDAE_PARAMETERS_FILE environment variable.
+  parameters_file = "/run/manidae/parameters.yaml"
+
+  # Optional: override or extend the resource footprint of instance states.
+  instance_states = {
+    suspended = { compute = 0, storage = 1, network = 1, gpu = 0 }
+    on        = { compute = 1, storage = 1, network = 1, gpu = 1 }
+  }
+
+  # Optional: verify the signed context file named by MANIDAE_CONTEXT_FILE.
+  # context_ed25519_public_key = file("${path.module}/manidae-context.pub")
 }
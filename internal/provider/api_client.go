@@ -0,0 +1,286 @@
+// Copyright (c) WANIX Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	defaultAPITimeout      = 30 * time.Second
+	defaultAPIMaxRetries   = 3
+	defaultAPIRetryWaitMin = 500 * time.Millisecond
+	defaultAPIRetryWaitMax = 8 * time.Second
+)
+
+// manidaeClientConfig is the resolved API configuration from the provider
+// block.
+type manidaeClientConfig struct {
+	Endpoint     string
+	Token        string
+	CABundleFile string
+	Timeout      time.Duration
+	MaxRetries   int
+	UserAgent    string
+}
+
+// manidaeClient talks to the Manidae control plane. Requests that fail with
+// a transport error, 429 or a 5xx response are retried with exponential
+// backoff, honouring `Retry-After`.
+type manidaeClient struct {
+	endpoint     *url.URL
+	token        string
+	userAgent    string
+	httpClient   *http.Client
+	maxRetries   int
+	retryWaitMin time.Duration
+	retryWaitMax time.Duration
+}
+
+// apiError is a non-2xx response from the control plane.
+type apiError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *apiError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("unexpected status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("unexpected status %d: %s", e.StatusCode, e.Message)
+}
+
+func newManidaeClient(config manidaeClientConfig) (*manidaeClient, error) {
+	endpoint, err := url.Parse(strings.TrimSpace(config.Endpoint))
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint: %w", err)
+	}
+	if (endpoint.Scheme != "https" && endpoint.Scheme != "http") || endpoint.Host == "" {
+		return nil, fmt.Errorf("endpoint %q must be an absolute http:// or https:// URL", config.Endpoint)
+	}
+	if config.Token == "" {
+		return nil, errors.New("a token is required")
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if config.CABundleFile != "" {
+		bundle, err := os.ReadFile(config.CABundleFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read CA bundle: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(bundle) {
+			return nil, fmt.Errorf("CA bundle %q contains no PEM certificates", config.CABundleFile)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+
+	timeout := config.Timeout
+	if timeout == 0 {
+		timeout = defaultAPITimeout
+	}
+
+	return &manidaeClient{
+		endpoint:     endpoint,
+		token:        config.Token,
+		userAgent:    config.UserAgent,
+		httpClient:   &http.Client{Transport: transport, Timeout: timeout},
+		maxRetries:   config.MaxRetries,
+		retryWaitMin: defaultAPIRetryWaitMin,
+		retryWaitMax: defaultAPIRetryWaitMax,
+	}, nil
+}
+
+// instanceContext fetches the context of an instance, in the same shape as
+// the payload of a signed context file.
+func (c *manidaeClient) instanceContext(ctx context.Context, instanceID int64) (*contextFile, error) {
+	requestPath := fmt.Sprintf("api/v1/instances/%d/context", instanceID)
+
+	file := &contextFile{Path: c.endpoint.JoinPath(requestPath).String()}
+	if err := c.getJSON(ctx, requestPath, &file.Payload); err != nil {
+		var statusErr *apiError
+		if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("instance %d not found", instanceID)
+		}
+		return nil, err
+	}
+	if id := file.Payload.Instance.ID; id != nil && *id != instanceID {
+		return nil, fmt.Errorf("requested instance %d but the response describes instance %d", instanceID, *id)
+	}
+	if err := file.decodeParameters(); err != nil {
+		return nil, fmt.Errorf("invalid response: %w", err)
+	}
+	return file, nil
+}
+
+// getJSON GETs requestPath relative to the endpoint and decodes the JSON
+// response into out.
+func (c *manidaeClient) getJSON(ctx context.Context, requestPath string, out any) error {
+	target := c.endpoint.JoinPath(requestPath).String()
+
+	for attempt := 0; ; attempt++ {
+		body, retryAfter, err := c.do(ctx, target)
+		if err == nil {
+			if err := json.Unmarshal(body, out); err != nil {
+				return fmt.Errorf("GET %s: invalid response: %w", target, err)
+			}
+			return nil
+		}
+
+		if !isRetryableAPIError(ctx, err) || attempt >= c.maxRetries {
+			return fmt.Errorf("GET %s: %w", target, err)
+		}
+
+		wait := c.backoff(attempt, retryAfter)
+		tflog.Debug(ctx, "Retrying Manidae API request", map[string]any{
+			"url":     target,
+			"attempt": attempt + 1,
+			"wait":    wait.String(),
+			"error":   err.Error(),
+		})
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("GET %s: %w", target, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// do performs a single attempt, returning the body of a 2xx response or the
+// `Retry-After` delay of a failed one.
+func (c *manidaeClient) do(ctx context.Context, target string) ([]byte, time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var retryAfter time.Duration
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+			retryAfter = time.Duration(seconds) * time.Second
+		}
+		return nil, retryAfter, &apiError{StatusCode: resp.StatusCode, Message: apiErrorMessage(body)}
+	}
+
+	return body, 0, nil
+}
+
+// backoff doubles the wait from retryWaitMin on every attempt, up to
+// retryWaitMax. A server-supplied `Retry-After` takes precedence but is
+// capped the same way.
+func (c *manidaeClient) backoff(attempt int, retryAfter time.Duration) time.Duration {
+	wait := retryAfter
+	if wait == 0 {
+		wait = c.retryWaitMin << attempt
+	}
+	if wait > c.retryWaitMax || wait <= 0 {
+		wait = c.retryWaitMax
+	}
+	return wait
+}
+
+func isRetryableAPIError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var statusErr *apiError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode == http.StatusTooManyRequests || (statusErr.StatusCode >= 500 && statusErr.StatusCode != http.StatusNotImplemented)
+	}
+
+	// Certificate errors will not go away on their own.
+	var certErr *tls.CertificateVerificationError
+	var unknownAuthority x509.UnknownAuthorityError
+	return !errors.As(err, &certErr) && !errors.As(err, &unknownAuthority)
+}
+
+// apiErrorMessage extracts `message` (or `error`) from a JSON error body,
+// falling back to the start of the raw body.
+func apiErrorMessage(body []byte) string {
+	var document struct {
+		Message string `json:"message"`
+		Error   string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &document); err == nil {
+		if document.Message != "" {
+			return document.Message
+		}
+		if document.Error != "" {
+			return document.Error
+		}
+	}
+
+	message := strings.TrimSpace(string(body))
+	if len(message) > 200 {
+		message = message[:200] + "..."
+	}
+	return message
+}
+
+// instanceContext returns the context every data source reads instead of
+// environment variables: the verified context file, or the control plane's
+// answer for `MANIDAE_INSTANCE_ID` when an endpoint is configured. Only a
+// successful answer is cached, so a failed request (for example one cancelled
+// with the data source read that made it) is retried by the next read; nil
+// means the environment variables apply.
+func (p *manidaeProviderData) instanceContext(ctx context.Context) (*contextFile, diag.Diagnostics) {
+	if p.context != nil || p.client == nil {
+		return p.context, nil
+	}
+
+	p.remoteContextMu.Lock()
+	defer p.remoteContextMu.Unlock()
+
+	if p.remoteContext != nil {
+		return p.remoteContext, nil
+	}
+
+	instanceID, diags := getRequiredUintEnvAsInt64("MANIDAE_INSTANCE_ID")
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	file, err := p.client.instanceContext(ctx, instanceID)
+	if err != nil {
+		diags.AddError("Manidae API request failed", fmt.Sprintf("cannot fetch the context of instance %d: %s", instanceID, err))
+		return nil, diags
+	}
+	p.remoteContext = file
+	return file, diags
+}
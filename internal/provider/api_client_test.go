@@ -0,0 +1,276 @@
+// Copyright (c) WANIX Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const testAPIContext = `{
+  "instance": {"id": 7, "connection_id": "cid", "identity": "ident", "state": "suspended"},
+  "action": "update",
+  "owner": {"username": "jane", "roles": ["admin"]},
+  "parameters": {"region": "eu-west-1"},
+  "previous_parameters": {"region": "us-east-1"}
+}`
+
+func newTestManidaeClient(t *testing.T, server *httptest.Server, config manidaeClientConfig) *manidaeClient {
+	t.Helper()
+
+	config.Endpoint = server.URL
+	if config.Token == "" {
+		config.Token = "t0ken"
+	}
+	client, err := newManidaeClient(config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	client.retryWaitMin = time.Millisecond
+	client.retryWaitMax = 5 * time.Millisecond
+	return client
+}
+
+func TestManidaeClientInstanceContext(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/instances/7/context" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer t0ken" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"message": "invalid token"}`))
+			return
+		}
+		_, _ = w.Write([]byte(testAPIContext))
+	}))
+	defer server.Close()
+
+	client := newTestManidaeClient(t, server, manidaeClientConfig{})
+	file, err := client.instanceContext(context.Background(), 7)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	instance, diags := file.instance()
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %#v", diags)
+	}
+	if instance.ID != 7 || instance.State != "suspended" || instance.Action != "update" {
+		t.Fatalf("unexpected instance: %#v", instance)
+	}
+	if file.Parameters["region"] != "eu-west-1" || file.PreviousParameters["region"] != "us-east-1" {
+		t.Fatalf("unexpected parameters: %#v, %#v", file.Parameters, file.PreviousParameters)
+	}
+
+	if _, err := client.instanceContext(context.Background(), 8); err == nil {
+		t.Fatalf("expected error for unknown instance, got none")
+	}
+
+	unauthorized := newTestManidaeClient(t, server, manidaeClientConfig{Token: "wrong"})
+	if _, err := unauthorized.instanceContext(context.Background(), 7); err == nil {
+		t.Fatalf("expected error for invalid token, got none")
+	}
+}
+
+func TestManidaeClientRetries(t *testing.T) {
+	t.Parallel()
+
+	t.Run("recovers", func(t *testing.T) {
+		var requests atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if requests.Add(1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			_, _ = w.Write([]byte(testAPIContext))
+		}))
+		defer server.Close()
+
+		client := newTestManidaeClient(t, server, manidaeClientConfig{MaxRetries: 3})
+		if _, err := client.instanceContext(context.Background(), 7); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := requests.Load(); got != 3 {
+			t.Fatalf("expected 3 requests, got %d", got)
+		}
+	})
+
+	t.Run("gives up", func(t *testing.T) {
+		var requests atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer server.Close()
+
+		client := newTestManidaeClient(t, server, manidaeClientConfig{MaxRetries: 2})
+		if _, err := client.instanceContext(context.Background(), 7); err == nil {
+			t.Fatalf("expected error, got none")
+		}
+		if got := requests.Load(); got != 3 {
+			t.Fatalf("expected 3 requests, got %d", got)
+		}
+	})
+
+	t.Run("client errors are not retried", func(t *testing.T) {
+		var requests atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			w.WriteHeader(http.StatusForbidden)
+		}))
+		defer server.Close()
+
+		client := newTestManidaeClient(t, server, manidaeClientConfig{MaxRetries: 3})
+		if _, err := client.instanceContext(context.Background(), 7); err == nil {
+			t.Fatalf("expected error, got none")
+		}
+		if got := requests.Load(); got != 1 {
+			t.Fatalf("expected 1 request, got %d", got)
+		}
+	})
+}
+
+func TestManidaeClientBackoff(t *testing.T) {
+	t.Parallel()
+
+	client := &manidaeClient{retryWaitMin: time.Second, retryWaitMax: 5 * time.Second}
+	for attempt, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second} {
+		if got := client.backoff(attempt, 0); got != want {
+			t.Errorf("attempt %d: expected %s, got %s", attempt, want, got)
+		}
+	}
+	if got := client.backoff(0, 3*time.Second); got != 3*time.Second {
+		t.Errorf("expected Retry-After to be honoured, got %s", got)
+	}
+	if got := client.backoff(0, time.Minute); got != 5*time.Second {
+		t.Errorf("expected Retry-After to be capped, got %s", got)
+	}
+}
+
+func TestManidaeClientCABundle(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(testAPIContext))
+	}))
+	defer server.Close()
+
+	// The test server's certificate is not trusted by the system roots.
+	untrusted := newTestManidaeClient(t, server, manidaeClientConfig{MaxRetries: 3})
+	if _, err := untrusted.instanceContext(context.Background(), 7); err == nil {
+		t.Fatalf("expected certificate error, got none")
+	}
+
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	certificate := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(bundle, certificate, 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	trusted := newTestManidaeClient(t, server, manidaeClientConfig{CABundleFile: bundle})
+	if _, err := trusted.instanceContext(context.Background(), 7); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestConfigureManidaeClient(t *testing.T) {
+	t.Setenv("MANIDAE_TOKEN", "")
+
+	if client, diags := configureManidaeClient(ManidaeProviderModel{Endpoint: types.StringNull()}, "test"); diags.HasError() || client != nil {
+		t.Fatalf("expected no client without endpoint, got %#v, %#v", client, diags)
+	}
+
+	data := ManidaeProviderModel{
+		Endpoint:     types.StringValue("https://manidae.example.com"),
+		Token:        types.StringNull(),
+		CABundleFile: types.StringNull(),
+		Timeout:      types.StringNull(),
+		MaxRetries:   types.Int64Null(),
+	}
+	if _, diags := configureManidaeClient(data, "test"); !diags.HasError() {
+		t.Fatalf("expected error without token, got none")
+	}
+
+	t.Setenv("MANIDAE_TOKEN", "t0ken")
+	client, diags := configureManidaeClient(data, "test")
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %#v", diags)
+	}
+	if client.token != "t0ken" || client.maxRetries != defaultAPIMaxRetries || client.httpClient.Timeout != defaultAPITimeout {
+		t.Fatalf("unexpected client: %#v", client)
+	}
+
+	data.Timeout = types.StringValue("soon")
+	if _, diags := configureManidaeClient(data, "test"); !diags.HasError() {
+		t.Fatalf("expected error for invalid timeout, got none")
+	}
+}
+
+func TestProviderDataInstanceContext_FetchesOnce(t *testing.T) {
+	t.Setenv("MANIDAE_INSTANCE_ID", "7")
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		_, _ = w.Write([]byte(testAPIContext))
+	}))
+	defer server.Close()
+
+	providerData := &manidaeProviderData{client: newTestManidaeClient(t, server, manidaeClientConfig{})}
+	for range 2 {
+		file, diags := providerData.instanceContext(context.Background())
+		if diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %#v", diags)
+		}
+		if owner, _ := file.owner(); owner.Username.ValueString() != "jane" {
+			t.Fatalf("unexpected owner: %#v", owner)
+		}
+	}
+	if got := requests.Load(); got != 1 {
+		t.Fatalf("expected 1 request, got %d", got)
+	}
+}
+
+func TestProviderDataInstanceContext_RetriesAfterFailure(t *testing.T) {
+	t.Setenv("MANIDAE_INSTANCE_ID", "7")
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(testAPIContext))
+	}))
+	defer server.Close()
+
+	providerData := &manidaeProviderData{client: newTestManidaeClient(t, server, manidaeClientConfig{MaxRetries: 0})}
+	if _, diags := providerData.instanceContext(context.Background()); !diags.HasError() {
+		t.Fatalf("expected error, got none")
+	}
+
+	for range 2 {
+		file, diags := providerData.instanceContext(context.Background())
+		if diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %#v", diags)
+		}
+		if owner, _ := file.owner(); owner.Username.ValueString() != "jane" {
+			t.Fatalf("unexpected owner: %#v", owner)
+		}
+	}
+	if got := requests.Load(); got != 2 {
+		t.Fatalf("expected 2 requests, got %d", got)
+	}
+}
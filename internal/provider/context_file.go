@@ -0,0 +1,343 @@
+// Copyright (c) WANIX Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Signature algorithms accepted in a context file.
+const (
+	contextAlgorithmHMACSHA256 = "hmac-sha256"
+	contextAlgorithmEd25519    = "ed25519"
+)
+
+// contextEnvelope is the document at `MANIDAE_CONTEXT_FILE`. The signature
+// covers the base64-decoded payload bytes, so verification does not depend on
+// how the JSON is formatted.
+type contextEnvelope struct {
+	Payload   string `json:"payload"`
+	Algorithm string `json:"algorithm"`
+	Signature string `json:"signature"`
+}
+
+// contextPayload is the signed instance context. It carries the values
+// otherwise read from `MANIDAE_INSTANCE_*`, `MANIDAE_ACTION`,
+// `MANIDAE_OWNER_*`, `MANIDAE_PRESET` and the hashed parameter and previous
+// parameter environment variables.
+type contextPayload struct {
+	Instance struct {
+		ID           *int64 `json:"id"`
+		ConnectionID string `json:"connection_id"`
+		Identity     string `json:"identity"`
+		State        string `json:"state"`
+	} `json:"instance"`
+	Action             string          `json:"action"`
+	Owner              *contextOwner   `json:"owner"`
+	Preset             string          `json:"preset"`
+	Parameters         json.RawMessage `json:"parameters"`
+	PreviousParameters json.RawMessage `json:"previous_parameters"`
+}
+
+type contextOwner struct {
+	Username     string   `json:"username"`
+	FullName     string   `json:"full_name"`
+	Email        string   `json:"email"`
+	Groups       []string `json:"groups"`
+	Roles        []string `json:"roles"`
+	SSHPublicKey string   `json:"ssh_public_key"`
+}
+
+// contextFile is a verified context file, or the same payload fetched from
+// the Manidae API (with Path set to its URL). Parameters and
+// PreviousParameters hold values as the raw strings the hashed environment
+// variables would carry, like parametersFile.
+type contextFile struct {
+	Path               string
+	Payload            contextPayload
+	Parameters         map[string]string
+	PreviousParameters map[string]string
+}
+
+// contextKey verifies context file signatures. Exactly one of HMACSecret and
+// Ed25519PublicKey is set, matching Algorithm.
+type contextKey struct {
+	Algorithm        string
+	HMACSecret       []byte
+	Ed25519PublicKey ed25519.PublicKey
+}
+
+// configureContextFile loads the context file at contextPath when one is
+// configured. Once a verification key is set the context file is mandatory
+// unless allowEnvFallback is true, since the environment variables it
+// replaces cannot be trusted; a context file without a key is refused.
+func configureContextFile(contextPath string, hmacSecret types.String, ed25519PublicKey types.String, allowEnvFallback bool) (*contextFile, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	var key *contextKey
+	switch {
+	case !hmacSecret.IsNull() && !ed25519PublicKey.IsNull():
+		diags.AddAttributeError(path.Root("context_ed25519_public_key"), "Invalid context key", "only one of `context_hmac_secret` and `context_ed25519_public_key` may be set")
+		return nil, diags
+	case !hmacSecret.IsNull():
+		if hmacSecret.ValueString() == "" {
+			diags.AddAttributeError(path.Root("context_hmac_secret"), "Invalid context key", "`context_hmac_secret` must not be empty")
+			return nil, diags
+		}
+		key = &contextKey{Algorithm: contextAlgorithmHMACSHA256, HMACSecret: []byte(hmacSecret.ValueString())}
+	case !ed25519PublicKey.IsNull():
+		publicKey, err := parseContextEd25519PublicKey(ed25519PublicKey.ValueString())
+		if err != nil {
+			diags.AddAttributeError(path.Root("context_ed25519_public_key"), "Invalid context key", err.Error())
+			return nil, diags
+		}
+		key = &contextKey{Algorithm: contextAlgorithmEd25519, Ed25519PublicKey: publicKey}
+	}
+
+	switch {
+	case contextPath != "" && key == nil:
+		diags.AddAttributeError(path.Root("context_file"), "Missing context key", fmt.Sprintf("context file %q cannot be verified: set `context_hmac_secret` or `context_ed25519_public_key`", contextPath))
+		return nil, diags
+	case contextPath == "" && key != nil && !allowEnvFallback:
+		diags.AddAttributeError(path.Root("context_file"), "Missing context file", "a context key is configured but neither `context_file` nor `MANIDAE_CONTEXT_FILE` is set; set `context_env_fallback = true` to read environment variables instead")
+		return nil, diags
+	case contextPath == "":
+		return nil, diags
+	}
+
+	file, err := loadContextFile(contextPath, *key)
+	if err != nil {
+		diags.AddAttributeError(path.Root("context_file"), "Invalid context file", err.Error())
+		return nil, diags
+	}
+	return file, diags
+}
+
+// parseContextEd25519PublicKey accepts a PEM-encoded `PUBLIC KEY` block or
+// the base64-encoded 32-byte raw key.
+func parseContextEd25519PublicKey(value string) (ed25519.PublicKey, error) {
+	value = strings.TrimSpace(value)
+
+	if block, _ := pem.Decode([]byte(value)); block != nil {
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		publicKey, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("expected an Ed25519 public key, got %T", key)
+		}
+		return publicKey, nil
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("expected a PEM block or base64-encoded key: %w", err)
+	}
+	if len(raw) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("expected %d key bytes, got %d", ed25519.PublicKeySize, len(raw))
+	}
+	return ed25519.PublicKey(raw), nil
+}
+
+// loadContextFile reads the context file at path and verifies its signature
+// with key. Any mismatch is an error: the file must not be used unless it is
+// exactly what the platform signed.
+func loadContextFile(path string, key contextKey) (*contextFile, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var envelope contextEnvelope
+	if err := json.Unmarshal(content, &envelope); err != nil {
+		return nil, fmt.Errorf("%s: expected a JSON object with `payload`, `algorithm` and `signature`: %w", path, err)
+	}
+
+	payload, err := base64.StdEncoding.DecodeString(envelope.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("%s: `payload` is not valid base64: %w", path, err)
+	}
+	signature, err := base64.StdEncoding.DecodeString(envelope.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("%s: `signature` is not valid base64: %w", path, err)
+	}
+
+	if err := verifyContextSignature(key, envelope.Algorithm, payload, signature); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	file := &contextFile{Path: path}
+	if err := json.Unmarshal(payload, &file.Payload); err != nil {
+		return nil, fmt.Errorf("%s: invalid payload: %w", path, err)
+	}
+	if err := file.decodeParameters(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return file, nil
+}
+
+// decodeParameters fills Parameters and PreviousParameters from the payload.
+func (f *contextFile) decodeParameters() error {
+	var err error
+	f.Parameters, err = decodeContextParameters(f.Payload.Parameters)
+	if err != nil {
+		return fmt.Errorf("`parameters`: %w", err)
+	}
+	f.PreviousParameters, err = decodeContextParameters(f.Payload.PreviousParameters)
+	if err != nil {
+		return fmt.Errorf("`previous_parameters`: %w", err)
+	}
+	return nil
+}
+
+// decodeContextParameters decodes a parameter object from the payload; an
+// absent or null object has no values.
+func decodeContextParameters(content json.RawMessage) (map[string]string, error) {
+	if len(content) == 0 || bytes.Equal(bytes.TrimSpace(content), []byte("null")) {
+		return map[string]string{}, nil
+	}
+	return decodeJSONParameters(content)
+}
+
+// verifyContextSignature checks signature over payload. The algorithm named
+// in the file must match the configured key, so a file cannot downgrade an
+// Ed25519 setup to HMAC or vice versa.
+func verifyContextSignature(key contextKey, algorithm string, payload []byte, signature []byte) error {
+	if algorithm != key.Algorithm {
+		return fmt.Errorf("signed with algorithm %q, but the provider is configured for %q", algorithm, key.Algorithm)
+	}
+
+	var valid bool
+	switch key.Algorithm {
+	case contextAlgorithmHMACSHA256:
+		mac := hmac.New(sha256.New, key.HMACSecret)
+		mac.Write(payload)
+		valid = hmac.Equal(mac.Sum(nil), signature)
+	case contextAlgorithmEd25519:
+		valid = ed25519.Verify(key.Ed25519PublicKey, payload, signature)
+	default:
+		return fmt.Errorf("unsupported algorithm %q", key.Algorithm)
+	}
+
+	if !valid {
+		return errors.New("signature verification failed; the context file has been modified or was signed with a different key")
+	}
+	return nil
+}
+
+// instance returns the instance context from the file (or API response),
+// with the same requirements as the environment variables it replaces.
+func (f *contextFile) instance() (instanceEnvironment, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	instance := f.Payload.Instance
+	if instance.ID == nil {
+		diags.AddError("Invalid instance context", fmt.Sprintf("instance context %q has no `instance.id`", f.Path))
+	} else if *instance.ID < 0 {
+		diags.AddError("Invalid instance context", fmt.Sprintf("instance context %q: `instance.id` must be a non-negative integer", f.Path))
+	}
+	for _, field := range []struct{ name, value string }{
+		{"instance.connection_id", instance.ConnectionID},
+		{"instance.identity", instance.Identity},
+		{"instance.state", instance.State},
+		{"action", f.Payload.Action},
+	} {
+		if strings.TrimSpace(field.value) == "" {
+			diags.AddError("Invalid instance context", fmt.Sprintf("instance context %q has no `%s`", f.Path, field.name))
+		}
+	}
+	if diags.HasError() {
+		return instanceEnvironment{}, diags
+	}
+
+	return instanceEnvironment{
+		ID:           *instance.ID,
+		ConnectionID: strings.TrimSpace(instance.ConnectionID),
+		Identity:     strings.TrimSpace(instance.Identity),
+		Action:       strings.TrimSpace(f.Payload.Action),
+		State:        strings.TrimSpace(instance.State),
+	}, diags
+}
+
+// owner returns the owner from the file. As with `MANIDAE_OWNER_*`, only the
+// username is required and empty fields are null.
+func (f *contextFile) owner() (ownerDataSourceModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	owner := f.Payload.Owner
+	if owner == nil || strings.TrimSpace(owner.Username) == "" {
+		diags.AddError("Invalid instance context", fmt.Sprintf("instance context %q has no `owner.username`", f.Path))
+		return ownerDataSourceModel{}, diags
+	}
+
+	return ownerDataSourceModel{
+		Username:     types.StringValue(strings.TrimSpace(owner.Username)),
+		FullName:     contextOptionalString(owner.FullName),
+		Email:        contextOptionalString(owner.Email),
+		Groups:       contextOptionalList(owner.Groups),
+		Roles:        contextOptionalList(owner.Roles),
+		SSHPublicKey: contextOptionalString(owner.SSHPublicKey),
+	}, diags
+}
+
+func contextOptionalString(value string) types.String {
+	if value = strings.TrimSpace(value); value == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(value)
+}
+
+func contextOptionalList(values []string) types.List {
+	elements := []attr.Value{}
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			elements = append(elements, types.StringValue(value))
+		}
+	}
+	if len(elements) == 0 {
+		return types.ListNull(types.StringType)
+	}
+	return types.ListValueMust(types.StringType, elements)
+}
+
+// previousParameterValue parses the value the parameter resolved to on the
+// previous build from `previous_parameters`, trying names in order. It
+// returns nil when the context has none.
+func (f *contextFile) previousParameterValue(parameterType string, unit string, names ...string) (attr.Value, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	for _, name := range names {
+		rawPrevious, ok := f.PreviousParameters[name]
+		if !ok {
+			continue
+		}
+
+		previous, parseDiags := parseParameterValue(parameterType, unit, rawPrevious, parameterSourceContext)
+		if parseDiags.HasError() {
+			diags.AddError("Invalid previous value", fmt.Sprintf("instance context %q: `previous_parameters.%s` does not hold a valid %s value", f.Path, name, parameterType))
+			return nil, diags
+		}
+		return previous, diags
+	}
+
+	return nil, diags
+}
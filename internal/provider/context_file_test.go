@@ -0,0 +1,195 @@
+// Copyright (c) WANIX Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const testContextPayload = `{
+  "instance": {"id": 42, "connection_id": "cid", "identity": "ident", "state": "on"},
+  "action": "create",
+  "owner": {"username": "jane", "groups": ["dev", " "], "email": "jane@example.com"},
+  "parameters": {"region": "eu-west-1", "cpu_count": 4}
+}`
+
+func writeContextFile(t *testing.T, payload string, algorithm string, sign func([]byte) []byte) string {
+	t.Helper()
+
+	content, err := json.Marshal(contextEnvelope{
+		Payload:   base64.StdEncoding.EncodeToString([]byte(payload)),
+		Algorithm: algorithm,
+		Signature: base64.StdEncoding.EncodeToString(sign([]byte(payload))),
+	})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "context.json")
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	return path
+}
+
+func signHMAC(secret string) func([]byte) []byte {
+	return func(payload []byte) []byte {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(payload)
+		return mac.Sum(nil)
+	}
+}
+
+func TestLoadContextFile_HMAC(t *testing.T) {
+	t.Parallel()
+
+	path := writeContextFile(t, testContextPayload, contextAlgorithmHMACSHA256, signHMAC("s3cret"))
+
+	file, err := loadContextFile(path, contextKey{Algorithm: contextAlgorithmHMACSHA256, HMACSecret: []byte("s3cret")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if file.Parameters["region"] != "eu-west-1" || file.Parameters["cpu_count"] != "4" {
+		t.Fatalf("unexpected parameters: %#v", file.Parameters)
+	}
+
+	instance, diags := file.instance()
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %#v", diags)
+	}
+	if instance.ID != 42 || instance.Action != "create" || instance.State != "on" {
+		t.Fatalf("unexpected instance: %#v", instance)
+	}
+
+	owner, diags := file.owner()
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %#v", diags)
+	}
+	if !owner.Username.Equal(types.StringValue("jane")) || !owner.FullName.IsNull() || len(owner.Groups.Elements()) != 1 {
+		t.Fatalf("unexpected owner: %#v", owner)
+	}
+
+	if _, err := loadContextFile(path, contextKey{Algorithm: contextAlgorithmHMACSHA256, HMACSecret: []byte("other")}); err == nil {
+		t.Fatalf("expected error for wrong secret, got none")
+	}
+}
+
+func TestLoadContextFile_Ed25519(t *testing.T) {
+	t.Parallel()
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	sign := func(payload []byte) []byte { return ed25519.Sign(privateKey, payload) }
+	key := contextKey{Algorithm: contextAlgorithmEd25519, Ed25519PublicKey: publicKey}
+
+	path := writeContextFile(t, testContextPayload, contextAlgorithmEd25519, sign)
+	if _, err := loadContextFile(path, key); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Run("tampered", func(t *testing.T) {
+		path := writeContextFile(t, testContextPayload, contextAlgorithmEd25519, func([]byte) []byte {
+			return sign([]byte(`{"action": "delete"}`))
+		})
+		if _, err := loadContextFile(path, key); err == nil {
+			t.Fatalf("expected error, got none")
+		}
+	})
+
+	t.Run("algorithm mismatch", func(t *testing.T) {
+		path := writeContextFile(t, testContextPayload, contextAlgorithmHMACSHA256, signHMAC(string(publicKey)))
+		if _, err := loadContextFile(path, key); err == nil {
+			t.Fatalf("expected error, got none")
+		}
+	})
+}
+
+func TestParseContextEd25519PublicKey(t *testing.T) {
+	t.Parallel()
+
+	publicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	for name, value := range map[string]string{
+		"pem":    string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})),
+		"base64": base64.StdEncoding.EncodeToString(publicKey),
+	} {
+		got, err := parseContextEd25519PublicKey(value)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if !got.Equal(publicKey) {
+			t.Fatalf("%s: key mismatch", name)
+		}
+	}
+
+	if _, err := parseContextEd25519PublicKey(base64.StdEncoding.EncodeToString([]byte("short"))); err == nil {
+		t.Fatalf("expected error for short key, got none")
+	}
+}
+
+func TestConfigureContextFile(t *testing.T) {
+	t.Parallel()
+
+	path := writeContextFile(t, testContextPayload, contextAlgorithmHMACSHA256, signHMAC("s3cret"))
+	secret := types.StringValue("s3cret")
+
+	if file, diags := configureContextFile("", types.StringNull(), types.StringNull(), false); diags.HasError() || file != nil {
+		t.Fatalf("expected no context without configuration, got %#v, %#v", file, diags)
+	}
+	if file, diags := configureContextFile(path, secret, types.StringNull(), false); diags.HasError() || file == nil {
+		t.Fatalf("expected context, got %#v", diags)
+	}
+	if _, diags := configureContextFile(path, types.StringNull(), types.StringNull(), false); !diags.HasError() {
+		t.Fatalf("expected error for context file without key, got none")
+	}
+	if _, diags := configureContextFile("", secret, types.StringNull(), false); !diags.HasError() {
+		t.Fatalf("expected error for missing context file, got none")
+	}
+	if file, diags := configureContextFile("", secret, types.StringNull(), true); diags.HasError() || file != nil {
+		t.Fatalf("expected environment fallback, got %#v, %#v", file, diags)
+	}
+	if _, diags := configureContextFile(path, secret, types.StringValue("key"), false); !diags.HasError() {
+		t.Fatalf("expected error for conflicting keys, got none")
+	}
+}
+
+func TestParameterSourcesLookup_ContextReplacesEnvironment(t *testing.T) {
+	envKey := ParameterEnvironmentVariable("region")
+	t.Setenv(envKey, "us-east-1")
+
+	context := &contextFile{Path: "context.json", Parameters: map[string]string{"region": "eu-west-1"}}
+	raw, source, _, diags := parameterSources{Name: "region", EnvKey: envKey, Context: context}.lookup()
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %#v", diags)
+	}
+	if raw != "eu-west-1" || source != parameterSourceContext {
+		t.Fatalf("expected context value, got %q from %q", raw, source)
+	}
+
+	_, source, _, _ = parameterSources{Name: "region", EnvKey: envKey, Context: &contextFile{}}.lookup()
+	if source != "" {
+		t.Fatalf("expected the environment variable to be ignored, got source %q", source)
+	}
+}
@@ -7,24 +7,58 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
-type instanceDataSource struct{}
+// `MANIDAE_ACTION` values for the lifecycle builds the platform runs.
+// instanceActionCreate is the build that creates the instance.
+const (
+	instanceActionCreate  = "create"
+	instanceActionStart   = "start"
+	instanceActionStop    = "stop"
+	instanceActionRestart = "restart"
+	instanceActionUpdate  = "update"
+	instanceActionDelete  = "delete"
+)
+
+var supportedInstanceActions = []string{
+	instanceActionCreate,
+	instanceActionStart,
+	instanceActionStop,
+	instanceActionRestart,
+	instanceActionUpdate,
+	instanceActionDelete,
+}
+
+// Ensure instanceDataSource satisfies the data source interfaces.
+var _ datasource.DataSourceWithConfigure = &instanceDataSource{}
+
+type instanceDataSource struct {
+	providerData *manidaeProviderData
+}
 
 type instanceDataSourceModel struct {
-	ID           types.Int64  `tfsdk:"id"`
-	ConnectionID types.String `tfsdk:"connection_id"`
-	Identity     types.String `tfsdk:"identity"`
-	Action       types.String `tfsdk:"action"`
-	State        types.String `tfsdk:"state"`
-	StartCount   types.Int64  `tfsdk:"start_count"`
+	ID            types.Int64  `tfsdk:"id"`
+	ConnectionID  types.String `tfsdk:"connection_id"`
+	Identity      types.String `tfsdk:"identity"`
+	Action        types.String `tfsdk:"action"`
+	CustomActions types.List   `tfsdk:"custom_actions"`
+	IsCreate      types.Bool   `tfsdk:"is_create"`
+	IsStart       types.Bool   `tfsdk:"is_start"`
+	IsStop        types.Bool   `tfsdk:"is_stop"`
+	IsUpdate      types.Bool   `tfsdk:"is_update"`
+	IsDelete      types.Bool   `tfsdk:"is_delete"`
+	State         types.String `tfsdk:"state"`
+	Counts        types.Map    `tfsdk:"counts"`
+	StartCount    types.Int64  `tfsdk:"start_count"`
 }
 
 func NewInstanceDataSource() datasource.DataSource {
@@ -37,7 +71,7 @@ func (d *instanceDataSource) Metadata(ctx context.Context, req datasource.Metada
 
 func (d *instanceDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		MarkdownDescription: "Reads Manidae instance context from environment variables.",
+		MarkdownDescription: "Reads Manidae instance context from the provider's `context_file`, from the Manidae API when `endpoint` is configured, or otherwise from environment variables. Each attribute names the environment variable it falls back to.",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.Int64Attribute{
 				Computed:            true,
@@ -53,69 +87,172 @@ func (d *instanceDataSource) Schema(ctx context.Context, req datasource.SchemaRe
 			},
 			"action": schema.StringAttribute{
 				Computed:            true,
-				MarkdownDescription: "Action from `MANIDAE_ACTION`.",
+				MarkdownDescription: "Action from `MANIDAE_ACTION`, lowercased. One of `create`, `start`, `stop`, `restart`, `update` or `delete`, unless listed in `custom_actions`.",
+			},
+			"custom_actions": schema.ListAttribute{
+				Optional:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "Additional `MANIDAE_ACTION` values the template handles itself. Any other unknown action fails the read.",
+			},
+			"is_create": schema.BoolAttribute{
+				Computed:            true,
+				MarkdownDescription: "Whether `action` is `create`.",
+			},
+			"is_start": schema.BoolAttribute{
+				Computed:            true,
+				MarkdownDescription: "Whether `action` is `start`.",
+			},
+			"is_stop": schema.BoolAttribute{
+				Computed:            true,
+				MarkdownDescription: "Whether `action` is `stop`.",
+			},
+			"is_update": schema.BoolAttribute{
+				Computed:            true,
+				MarkdownDescription: "Whether `action` is `update`.",
+			},
+			"is_delete": schema.BoolAttribute{
+				Computed:            true,
+				MarkdownDescription: "Whether `action` is `delete`.",
 			},
 			"state": schema.StringAttribute{
 				Computed:            true,
-				MarkdownDescription: "Instance state from `MANIDAE_INSTANCE_STATE` (`on` or `off`).",
+				MarkdownDescription: "Instance state from `MANIDAE_INSTANCE_STATE`, lowercased. One of `on`, `off`, `suspended`, `hibernated` or a state declared in the provider's `instance_states`.",
+			},
+			"counts": schema.MapAttribute{
+				Computed:            true,
+				ElementType:         types.Int64Type,
+				MarkdownDescription: "Number of resources of each class (`compute`, `storage`, `network` and any class from the provider's `instance_states`) that should exist in `state`, e.g. `{compute = 0, storage = 1, network = 1}` when `suspended`.",
 			},
 			"start_count": schema.Int64Attribute{
 				Computed:            true,
-				MarkdownDescription: "Derived from `state`: `1` when `on`, otherwise `0`.",
+				MarkdownDescription: "Derived from `state`: the `compute` entry of `counts`, so `1` when `on` and `0` otherwise by default.",
 			},
 		},
 	}
 }
 
+func (d *instanceDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*manidaeProviderData)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected Data Source Configure Type", fmt.Sprintf("Expected *manidaeProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData))
+		return
+	}
+
+	d.providerData = providerData
+}
+
 func (d *instanceDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	var data instanceDataSourceModel
 
-	id, idDiags := getRequiredUintEnvAsInt64("MANIDAE_INSTANCE_ID")
-	resp.Diagnostics.Append(idDiags...)
-	if resp.Diagnostics.HasError() {
-		return
+	var instanceContext *contextFile
+	if d.providerData != nil {
+		var contextDiags diag.Diagnostics
+		instanceContext, contextDiags = d.providerData.instanceContext(ctx)
+		resp.Diagnostics.Append(contextDiags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
 	}
 
-	connectionID, connectionDiags := getRequiredEnvString("MANIDAE_CONNECTION_ID")
-	resp.Diagnostics.Append(connectionDiags...)
+	var instance instanceEnvironment
+	var instanceDiags diag.Diagnostics
+	if instanceContext != nil {
+		instance, instanceDiags = instanceContext.instance()
+	} else {
+		instance, instanceDiags = readInstanceEnvironment()
+	}
+	resp.Diagnostics.Append(instanceDiags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	identity, identityDiags := getRequiredEnvString("MANIDAE_IDENTITY")
-	resp.Diagnostics.Append(identityDiags...)
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	action, actionDiags := getRequiredEnvString("MANIDAE_ACTION")
+	var customActions []string
+	if !data.CustomActions.IsNull() {
+		resp.Diagnostics.Append(data.CustomActions.ElementsAs(ctx, &customActions, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	action, actionDiags := resolveInstanceAction(instance.Action, customActions)
 	resp.Diagnostics.Append(actionDiags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	state, stateDiags := getRequiredEnvString("MANIDAE_INSTANCE_STATE")
-	resp.Diagnostics.Append(stateDiags...)
+	footprints := defaultInstanceFootprints()
+	if d.providerData != nil && d.providerData.instanceFootprints != nil {
+		footprints = d.providerData.instanceFootprints
+	}
+
+	counts, countsDiags := footprints.counts(instance.State)
+	resp.Diagnostics.Append(countsDiags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	startCount, startCountDiags := deriveStartCount(state)
-	resp.Diagnostics.Append(startCountDiags...)
+	countsValue, countsValueDiags := types.MapValueFrom(ctx, types.Int64Type, counts)
+	resp.Diagnostics.Append(countsValueDiags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	data.ID = types.Int64Value(id)
-	data.ConnectionID = types.StringValue(connectionID)
-	data.Identity = types.StringValue(identity)
+	data.ID = types.Int64Value(instance.ID)
+	data.ConnectionID = types.StringValue(instance.ConnectionID)
+	data.Identity = types.StringValue(instance.Identity)
 	data.Action = types.StringValue(action)
-	data.State = types.StringValue(state)
-	data.StartCount = types.Int64Value(startCount)
+	data.IsCreate = types.BoolValue(action == instanceActionCreate)
+	data.IsStart = types.BoolValue(action == instanceActionStart)
+	data.IsStop = types.BoolValue(action == instanceActionStop)
+	data.IsUpdate = types.BoolValue(action == instanceActionUpdate)
+	data.IsDelete = types.BoolValue(action == instanceActionDelete)
+	data.State = types.StringValue(strings.ToLower(instance.State))
+	data.Counts = countsValue
+	data.StartCount = types.Int64Value(counts[instanceResourceCompute])
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
+// instanceEnvironment is the instance context the platform supplies, either
+// in a verified context file or as environment variables.
+type instanceEnvironment struct {
+	ID           int64
+	ConnectionID string
+	Identity     string
+	Action       string
+	State        string
+}
+
+// readInstanceEnvironment reads the instance context from environment
+// variables, reporting every missing or invalid one.
+func readInstanceEnvironment() (instanceEnvironment, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var instance instanceEnvironment
+
+	var fieldDiags diag.Diagnostics
+	instance.ID, fieldDiags = getRequiredUintEnvAsInt64("MANIDAE_INSTANCE_ID")
+	diags.Append(fieldDiags...)
+	instance.ConnectionID, fieldDiags = getRequiredEnvString("MANIDAE_CONNECTION_ID")
+	diags.Append(fieldDiags...)
+	instance.Identity, fieldDiags = getRequiredEnvString("MANIDAE_IDENTITY")
+	diags.Append(fieldDiags...)
+	instance.Action, fieldDiags = getRequiredEnvString("MANIDAE_ACTION")
+	diags.Append(fieldDiags...)
+	instance.State, fieldDiags = getRequiredEnvString("MANIDAE_INSTANCE_STATE")
+	diags.Append(fieldDiags...)
+
+	return instance, diags
+}
+
 func getRequiredEnvString(key string) (string, diag.Diagnostics) {
 	var diags diag.Diagnostics
 
@@ -151,16 +288,21 @@ func getRequiredUintEnvAsInt64(key string) (int64, diag.Diagnostics) {
 	return int64(uintValue), diags
 }
 
-func deriveStartCount(state string) (int64, diag.Diagnostics) {
+// resolveInstanceAction normalizes `MANIDAE_ACTION` and checks it against the
+// supported actions and the template's `custom_actions`.
+func resolveInstanceAction(action string, customActions []string) (string, diag.Diagnostics) {
 	var diags diag.Diagnostics
 
-	switch strings.ToLower(strings.TrimSpace(state)) {
-	case "on":
-		return 1, diags
-	case "off":
-		return 0, diags
-	default:
-		diags.AddError("Invalid instance state", "MANIDAE_INSTANCE_STATE must be either \"on\" or \"off\"")
-		return 0, diags
+	normalized := strings.ToLower(strings.TrimSpace(action))
+	if slices.Contains(supportedInstanceActions, normalized) {
+		return normalized, diags
 	}
+	for _, custom := range customActions {
+		if strings.ToLower(strings.TrimSpace(custom)) == normalized {
+			return normalized, diags
+		}
+	}
+
+	diags.AddAttributeError(path.Root("custom_actions"), "Invalid instance action", fmt.Sprintf("action %q is not a supported action (supported: %s); list it in `custom_actions` if the template handles it", action, quoteJoin(supportedInstanceActions)))
+	return "", diags
 }
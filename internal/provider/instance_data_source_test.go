@@ -10,45 +10,47 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 )
 
-func TestDeriveStartCount(t *testing.T) {
-	t.Run("on", func(t *testing.T) {
-		got, diags := deriveStartCount("on")
-		if diags.HasError() {
-			t.Fatalf("unexpected diagnostics: %#v", diags)
-		}
-		if got != 1 {
-			t.Fatalf("expected 1, got %d", got)
-		}
-	})
+func TestGetRequiredEnvString(t *testing.T) {
+	t.Setenv("MANIDAE_CONNECTION_ID", "  abc123  ")
+
+	got, diags := getRequiredEnvString("MANIDAE_CONNECTION_ID")
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %#v", diags)
+	}
+	if got != "abc123" {
+		t.Fatalf("expected %q, got %q", "abc123", got)
+	}
+}
+
+func TestResolveInstanceAction(t *testing.T) {
+	t.Parallel()
 
-	t.Run("off", func(t *testing.T) {
-		got, diags := deriveStartCount("off")
+	t.Run("supported", func(t *testing.T) {
+		got, diags := resolveInstanceAction(" Update ", nil)
 		if diags.HasError() {
 			t.Fatalf("unexpected diagnostics: %#v", diags)
 		}
-		if got != 0 {
-			t.Fatalf("expected 0, got %d", got)
+		if got != instanceActionUpdate {
+			t.Fatalf("expected %q, got %q", instanceActionUpdate, got)
 		}
 	})
 
-	t.Run("invalid", func(t *testing.T) {
-		_, diags := deriveStartCount("maybe")
+	t.Run("unknown", func(t *testing.T) {
+		_, diags := resolveInstanceAction("creat", nil)
 		if !diags.HasError() {
 			t.Fatalf("expected error, got none")
 		}
 	})
-}
-
-func TestGetRequiredEnvString(t *testing.T) {
-	t.Setenv("MANIDAE_CONNECTION_ID", "  abc123  ")
 
-	got, diags := getRequiredEnvString("MANIDAE_CONNECTION_ID")
-	if diags.HasError() {
-		t.Fatalf("unexpected diagnostics: %#v", diags)
-	}
-	if got != "abc123" {
-		t.Fatalf("expected %q, got %q", "abc123", got)
-	}
+	t.Run("custom", func(t *testing.T) {
+		got, diags := resolveInstanceAction("snapshot", []string{"Snapshot"})
+		if diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %#v", diags)
+		}
+		if got != "snapshot" {
+			t.Fatalf("expected %q, got %q", "snapshot", got)
+		}
+	})
 }
 
 func TestInstanceDataSourceSchema_HasIdentity(t *testing.T) {
@@ -67,7 +69,7 @@ func TestInstanceDataSourceSchema_HasIdentity(t *testing.T) {
 func TestInstanceDataSourceRead_RequiresIdentity(t *testing.T) {
 	t.Setenv("MANIDAE_INSTANCE_ID", "1")
 	t.Setenv("MANIDAE_CONNECTION_ID", "cid")
-	t.Setenv("MANIDAE_ACTION", "action")
+	t.Setenv("MANIDAE_ACTION", "create")
 	t.Setenv("MANIDAE_INSTANCE_STATE", "on")
 
 	ds := NewInstanceDataSource()
@@ -0,0 +1,97 @@
+// Copyright (c) WANIX Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// `MANIDAE_INSTANCE_STATE` values known to the provider. Suspended instances
+// keep their disks and addresses but release compute; hibernated ones keep
+// only their disks.
+const (
+	instanceStateOn         = "on"
+	instanceStateOff        = "off"
+	instanceStateSuspended  = "suspended"
+	instanceStateHibernated = "hibernated"
+)
+
+// Resource classes counted in `counts`. `start_count` is the compute count.
+const (
+	instanceResourceCompute = "compute"
+	instanceResourceStorage = "storage"
+	instanceResourceNetwork = "network"
+)
+
+// instanceFootprints maps an instance state to the number of resources of
+// each class that should exist in it.
+type instanceFootprints map[string]map[string]int64
+
+func defaultInstanceFootprints() instanceFootprints {
+	return instanceFootprints{
+		instanceStateOn:         {instanceResourceCompute: 1, instanceResourceStorage: 1, instanceResourceNetwork: 1},
+		instanceStateOff:        {instanceResourceCompute: 0, instanceResourceStorage: 1, instanceResourceNetwork: 0},
+		instanceStateSuspended:  {instanceResourceCompute: 0, instanceResourceStorage: 1, instanceResourceNetwork: 1},
+		instanceStateHibernated: {instanceResourceCompute: 0, instanceResourceStorage: 1, instanceResourceNetwork: 0},
+	}
+}
+
+// mergeInstanceFootprints applies the provider's `instance_states` to the
+// default table. An override replaces a state's footprint entirely and may
+// introduce new states.
+func mergeInstanceFootprints(overrides map[string]map[string]int64) (instanceFootprints, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	footprints := defaultInstanceFootprints()
+	for _, state := range slices.Sorted(maps.Keys(overrides)) {
+		statePath := path.Root("instance_states").AtMapKey(state)
+		normalized := strings.ToLower(strings.TrimSpace(state))
+		if normalized == "" || normalized != state {
+			diags.AddAttributeError(statePath, "Invalid instance state", fmt.Sprintf("state %q must be a non-empty lowercase name without surrounding whitespace", state))
+			continue
+		}
+
+		footprint := make(map[string]int64, len(overrides[state]))
+		for class, count := range overrides[state] {
+			if count < 0 {
+				diags.AddAttributeError(statePath.AtMapKey(class), "Invalid instance state", fmt.Sprintf("state %q: count for %q must not be negative, got %d", state, class, count))
+				continue
+			}
+			footprint[class] = count
+		}
+		footprints[state] = footprint
+	}
+
+	if diags.HasError() {
+		return nil, diags
+	}
+	return footprints, diags
+}
+
+// counts returns the footprint of state. Every resource class used by any
+// state is included, so templates can index `counts` without checking for
+// missing keys; classes a state does not list count as zero.
+func (f instanceFootprints) counts(state string) (map[string]int64, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	footprint, ok := f[strings.ToLower(strings.TrimSpace(state))]
+	if !ok {
+		diags.AddError("Invalid instance state", fmt.Sprintf("instance state %q is not a known state (supported: %s); additional states can be declared in the provider's `instance_states`", state, quoteJoin(slices.Sorted(maps.Keys(f)))))
+		return nil, diags
+	}
+
+	counts := make(map[string]int64)
+	for _, other := range f {
+		for class := range other {
+			counts[class] = footprint[class]
+		}
+	}
+	return counts, diags
+}
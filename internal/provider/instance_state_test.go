@@ -0,0 +1,97 @@
+// Copyright (c) WANIX Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestInstanceFootprintsCounts(t *testing.T) {
+	t.Parallel()
+
+	footprints := defaultInstanceFootprints()
+
+	t.Run("on", func(t *testing.T) {
+		got, diags := footprints.counts("on")
+		if diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %#v", diags)
+		}
+		if got[instanceResourceCompute] != 1 {
+			t.Fatalf("expected compute 1, got %v", got)
+		}
+	})
+
+	t.Run("off", func(t *testing.T) {
+		got, diags := footprints.counts("OFF")
+		if diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %#v", diags)
+		}
+		if got[instanceResourceCompute] != 0 {
+			t.Fatalf("expected compute 0, got %v", got)
+		}
+	})
+
+	t.Run("suspended", func(t *testing.T) {
+		got, diags := footprints.counts("suspended")
+		if diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %#v", diags)
+		}
+		want := map[string]int64{instanceResourceCompute: 0, instanceResourceStorage: 1, instanceResourceNetwork: 1}
+		if !maps.Equal(got, want) {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, diags := footprints.counts("maybe")
+		if !diags.HasError() {
+			t.Fatalf("expected error, got none")
+		}
+	})
+}
+
+func TestMergeInstanceFootprints(t *testing.T) {
+	t.Parallel()
+
+	footprints, diags := mergeInstanceFootprints(map[string]map[string]int64{
+		"on":       {"compute": 2, "gpu": 1},
+		"draining": {"compute": 1},
+	})
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %#v", diags)
+	}
+
+	got, diags := footprints.counts("on")
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %#v", diags)
+	}
+	want := map[string]int64{"compute": 2, "gpu": 1, "storage": 0, "network": 0}
+	if !maps.Equal(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	// Classes introduced by an override are filled in for every state.
+	got, diags = footprints.counts("suspended")
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %#v", diags)
+	}
+	if count, ok := got["gpu"]; !ok || count != 0 {
+		t.Fatalf("expected gpu 0, got %v", got)
+	}
+
+	if _, diags := footprints.counts("draining"); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %#v", diags)
+	}
+
+	for name, overrides := range map[string]map[string]map[string]int64{
+		"negative":  {"on": {"compute": -1}},
+		"uppercase": {"On": {"compute": 1}},
+		"empty":     {"": {"compute": 1}},
+	} {
+		if _, diags := mergeInstanceFootprints(overrides); !diags.HasError() {
+			t.Errorf("%s: expected error, got none", name)
+		}
+	}
+}
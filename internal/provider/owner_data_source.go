@@ -0,0 +1,222 @@
+// Copyright (c) WANIX Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/mail"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure ownerDataSource satisfies the data source interfaces.
+var _ datasource.DataSourceWithConfigure = &ownerDataSource{}
+
+type ownerDataSource struct {
+	providerData *manidaeProviderData
+}
+
+type ownerDataSourceModel struct {
+	ID           types.String `tfsdk:"id"`
+	Username     types.String `tfsdk:"username"`
+	FullName     types.String `tfsdk:"full_name"`
+	Email        types.String `tfsdk:"email"`
+	Groups       types.List   `tfsdk:"groups"`
+	Roles        types.List   `tfsdk:"roles"`
+	SSHPublicKey types.String `tfsdk:"ssh_public_key"`
+}
+
+func NewOwnerDataSource() datasource.DataSource {
+	return &ownerDataSource{}
+}
+
+func (d *ownerDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_owner"
+}
+
+func (d *ownerDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Reads the instance owner from the `owner` object of the provider's `context_file`, from the Manidae API when `endpoint` is configured, or otherwise from `MANIDAE_OWNER_*` environment variables. Only the username is required; other attributes are null when their field or variable is unset or empty.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Internal identifier (same as `username`).",
+			},
+			"username": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Username from `MANIDAE_OWNER_USERNAME` (required).",
+			},
+			"full_name": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Full name from `MANIDAE_OWNER_FULL_NAME`.",
+			},
+			"email": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Email address from `MANIDAE_OWNER_EMAIL`.",
+			},
+			"groups": schema.ListAttribute{
+				Computed:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "Groups from `MANIDAE_OWNER_GROUPS`, a comma-separated list.",
+			},
+			"roles": schema.ListAttribute{
+				Computed:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "Roles from `MANIDAE_OWNER_ROLES`, a comma-separated list.",
+			},
+			"ssh_public_key": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "SSH public key in `authorized_keys` format from `MANIDAE_OWNER_SSH_PUBLIC_KEY`.",
+			},
+		},
+	}
+}
+
+func (d *ownerDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*manidaeProviderData)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected Data Source Configure Type", fmt.Sprintf("Expected *manidaeProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData))
+		return
+	}
+
+	d.providerData = providerData
+}
+
+func (d *ownerDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data ownerDataSourceModel
+	var dataDiags diag.Diagnostics
+	emailSource, sshPublicKeySource := `environment variable "MANIDAE_OWNER_EMAIL"`, `environment variable "MANIDAE_OWNER_SSH_PUBLIC_KEY"`
+	var instanceContext *contextFile
+	if d.providerData != nil {
+		instanceContext, dataDiags = d.providerData.instanceContext(ctx)
+		resp.Diagnostics.Append(dataDiags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if instanceContext != nil {
+		data, dataDiags = instanceContext.owner()
+		emailSource = fmt.Sprintf("`owner.email` in instance context %q", instanceContext.Path)
+		sshPublicKeySource = fmt.Sprintf("`owner.ssh_public_key` in instance context %q", instanceContext.Path)
+	} else {
+		data, dataDiags = readOwnerEnvironment()
+	}
+	resp.Diagnostics.Append(dataDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(validateOwnerEmail(emailSource, data.Email)...)
+	resp.Diagnostics.Append(validateOwnerSSHPublicKey(sshPublicKeySource, data.SSHPublicKey)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.ID = data.Username
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// readOwnerEnvironment reads the owner from `MANIDAE_OWNER_*`.
+func readOwnerEnvironment() (ownerDataSourceModel, diag.Diagnostics) {
+	username, diags := getRequiredEnvString("MANIDAE_OWNER_USERNAME")
+	if diags.HasError() {
+		return ownerDataSourceModel{}, diags
+	}
+
+	return ownerDataSourceModel{
+		Username:     types.StringValue(username),
+		FullName:     getOptionalEnvString("MANIDAE_OWNER_FULL_NAME"),
+		Email:        getOptionalEnvString("MANIDAE_OWNER_EMAIL"),
+		Groups:       getOptionalEnvList("MANIDAE_OWNER_GROUPS"),
+		Roles:        getOptionalEnvList("MANIDAE_OWNER_ROLES"),
+		SSHPublicKey: getOptionalEnvString("MANIDAE_OWNER_SSH_PUBLIC_KEY"),
+	}, diags
+}
+
+// getOptionalEnvString returns the trimmed value of key, or null when it is
+// unset or empty.
+func getOptionalEnvString(key string) types.String {
+	value := strings.TrimSpace(os.Getenv(key))
+	if value == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(value)
+}
+
+// getOptionalEnvList splits a comma-separated value of key, or returns null
+// when it is unset or empty. Empty entries are dropped.
+func getOptionalEnvList(key string) types.List {
+	raw := getOptionalEnvString(key)
+	if raw.IsNull() {
+		return types.ListNull(types.StringType)
+	}
+
+	elements := []attr.Value{}
+	for _, entry := range strings.Split(raw.ValueString(), ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			elements = append(elements, types.StringValue(entry))
+		}
+	}
+	return types.ListValueMust(types.StringType, elements)
+}
+
+// validateOwnerEmail checks that email is a bare address. source describes
+// where the value came from, for the error message.
+func validateOwnerEmail(source string, email types.String) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if email.IsNull() {
+		return diags
+	}
+
+	address, err := mail.ParseAddress(email.ValueString())
+	if err != nil || address.Address != email.ValueString() {
+		diags.AddError("Invalid owner email", fmt.Sprintf("%s must be a bare email address such as \"jane@example.com\"", source))
+	}
+	return diags
+}
+
+// validateOwnerSSHPublicKey checks the `authorized_keys` shape of a key: a
+// key type followed by base64-encoded key data and an optional comment.
+// source describes where the value came from, for the error message.
+func validateOwnerSSHPublicKey(source string, publicKey types.String) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if publicKey.IsNull() {
+		return diags
+	}
+
+	fields := strings.Fields(publicKey.ValueString())
+	if len(fields) < 2 || !isSSHPublicKeyType(fields[0]) {
+		diags.AddError("Invalid owner SSH public key", fmt.Sprintf("%s must be an SSH public key in authorized_keys format, e.g. \"ssh-ed25519 AAAA... jane@laptop\"", source))
+		return diags
+	}
+	if _, err := base64.StdEncoding.DecodeString(fields[1]); err != nil {
+		diags.AddError("Invalid owner SSH public key", fmt.Sprintf("%s has invalid key data: %s", source, err))
+	}
+	return diags
+}
+
+func isSSHPublicKeyType(keyType string) bool {
+	for _, prefix := range []string{"ssh-", "ecdsa-sha2-", "sk-ssh-", "sk-ecdsa-sha2-"} {
+		if strings.HasPrefix(keyType, prefix) {
+			return true
+		}
+	}
+	return false
+}
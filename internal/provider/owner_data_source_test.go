@@ -0,0 +1,95 @@
+// Copyright (c) WANIX Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestGetOptionalEnvString(t *testing.T) {
+	t.Setenv("MANIDAE_OWNER_FULL_NAME", "  Jane Doe  ")
+	t.Setenv("MANIDAE_OWNER_EMAIL", "   ")
+
+	if got := getOptionalEnvString("MANIDAE_OWNER_FULL_NAME"); !got.Equal(types.StringValue("Jane Doe")) {
+		t.Fatalf("expected %q, got %s", "Jane Doe", got)
+	}
+	if got := getOptionalEnvString("MANIDAE_OWNER_EMAIL"); !got.IsNull() {
+		t.Fatalf("expected null for blank value, got %s", got)
+	}
+	if got := getOptionalEnvString("MANIDAE_OWNER_UNSET"); !got.IsNull() {
+		t.Fatalf("expected null for unset value, got %s", got)
+	}
+}
+
+func TestGetOptionalEnvList(t *testing.T) {
+	t.Setenv("MANIDAE_OWNER_GROUPS", " dev, ,ops ,")
+
+	got := getOptionalEnvList("MANIDAE_OWNER_GROUPS")
+	var groups []string
+	if diags := got.ElementsAs(context.Background(), &groups, false); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %#v", diags)
+	}
+	if len(groups) != 2 || groups[0] != "dev" || groups[1] != "ops" {
+		t.Fatalf("expected [dev ops], got %v", groups)
+	}
+
+	if got := getOptionalEnvList("MANIDAE_OWNER_ROLES"); !got.IsNull() {
+		t.Fatalf("expected null for unset value, got %s", got)
+	}
+}
+
+func TestValidateOwnerEmail(t *testing.T) {
+	t.Parallel()
+
+	for value, wantError := range map[string]bool{
+		"jane@example.com":          false,
+		"Jane <jane@example.com>":   true,
+		"not-an-email":              true,
+		"jane@example.com, bob@x.y": true,
+	} {
+		diags := validateOwnerEmail(`environment variable "MANIDAE_OWNER_EMAIL"`, types.StringValue(value))
+		if diags.HasError() != wantError {
+			t.Errorf("%q: expected error %t, got %#v", value, wantError, diags)
+		}
+	}
+
+	if diags := validateOwnerEmail(`environment variable "MANIDAE_OWNER_EMAIL"`, types.StringNull()); diags.HasError() {
+		t.Fatalf("unexpected diagnostics for null: %#v", diags)
+	}
+}
+
+func TestValidateOwnerSSHPublicKey(t *testing.T) {
+	t.Parallel()
+
+	for value, wantError := range map[string]bool{
+		"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIG8W jane@laptop": false,
+		"ecdsa-sha2-nistp256 AAAAE2VjZHNh":                     false,
+		"ssh-ed25519":                                          true,
+		"AAAAC3NzaC1lZDI1NTE5 jane@laptop":                     true,
+		"ssh-rsa not*base64":                                   true,
+	} {
+		diags := validateOwnerSSHPublicKey(`environment variable "MANIDAE_OWNER_SSH_PUBLIC_KEY"`, types.StringValue(value))
+		if diags.HasError() != wantError {
+			t.Errorf("%q: expected error %t, got %#v", value, wantError, diags)
+		}
+	}
+}
+
+func TestOwnerDataSourceRead_RequiresUsername(t *testing.T) {
+	t.Setenv("MANIDAE_OWNER_USERNAME", "")
+	t.Setenv("MANIDAE_OWNER_EMAIL", "jane@example.com")
+
+	ds := NewOwnerDataSource()
+
+	var resp datasource.ReadResponse
+	ds.Read(context.Background(), datasource.ReadRequest{}, &resp)
+
+	if !resp.Diagnostics.HasError() {
+		t.Fatalf("expected error, got none")
+	}
+}
@@ -0,0 +1,196 @@
+// Copyright (c) WANIX Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+// parameterConditionModel is a `visible_when` or `required_when` block. It
+// holds when the referenced parameter resolved to one of `values`.
+type parameterConditionModel struct {
+	Parameter types.String `tfsdk:"parameter"`
+	Values    types.List   `tfsdk:"values"`
+}
+
+// parameterResolution is what other parameters' conditions may inspect about
+// a parameter that has been read.
+type parameterResolution struct {
+	Type      string
+	Unit      string
+	Value     attr.Value
+	Sensitive bool
+}
+
+// validateParameterConditions checks the parts of `visible_when` and
+// `required_when` that do not depend on other parameters. Hidden parameters
+// resolve to `default`, so `visible_when` requires one.
+func validateParameterConditions(ctx context.Context, name types.String, defaultValue types.Dynamic, visibleWhen types.List, requiredWhen types.List) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if !visibleWhen.IsUnknown() && len(visibleWhen.Elements()) > 0 && defaultValue.IsNull() {
+		diags.AddAttributeError(path.Root("default"), "Missing default", "parameters with `visible_when` require `default`, which applies whenever the parameter is hidden")
+	}
+
+	for _, block := range []struct {
+		name       string
+		conditions types.List
+	}{{"visible_when", visibleWhen}, {"required_when", requiredWhen}} {
+		if block.conditions.IsUnknown() {
+			continue
+		}
+		for i, element := range block.conditions.Elements() {
+			object, ok := element.(types.Object)
+			if !ok || object.IsUnknown() {
+				continue
+			}
+			var condition parameterConditionModel
+			diags.Append(object.As(ctx, &condition, basetypes.ObjectAsOptions{})...)
+			if diags.HasError() {
+				return diags
+			}
+
+			conditionPath := path.Root(block.name).AtListIndex(i)
+			if !condition.Parameter.IsUnknown() && !name.IsUnknown() && condition.Parameter.ValueString() == name.ValueString() {
+				diags.AddAttributeError(conditionPath.AtName("parameter"), "Invalid condition", fmt.Sprintf("`%s` cannot reference the parameter itself", block.name))
+			}
+			if !condition.Values.IsUnknown() && len(condition.Values.Elements()) == 0 {
+				diags.AddAttributeError(conditionPath.AtName("values"), "Invalid condition", "`values` must list at least one value")
+			}
+		}
+	}
+
+	return diags
+}
+
+// evaluateParameterConditions reports whether every condition holds, along
+// with a description of the parameters that satisfied them. Referenced
+// parameters must already have been read.
+func (r *parameterRegistry) evaluateParameterConditions(ctx context.Context, blockName string, conditions []parameterConditionModel) (bool, []string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	holds := true
+	var triggers []string
+	for i, condition := range conditions {
+		conditionPath := path.Root(blockName).AtListIndex(i)
+		name := condition.Parameter.ValueString()
+
+		resolution, ok := r.lookupResolution(name)
+		if !ok {
+			diags.AddAttributeError(conditionPath.AtName("parameter"), "Unknown parameter", fmt.Sprintf("parameter %q has not been read; set `parameter = data.manidae_parameter.<name>.name` so it is read first", name))
+			continue
+		}
+
+		var values []string
+		diags.Append(condition.Values.ElementsAs(ctx, &values, false)...)
+		if diags.HasError() {
+			return false, nil, diags
+		}
+
+		matches, err := parameterConditionMatches(resolution, values)
+		if err != nil {
+			diags.AddAttributeError(conditionPath.AtName("values"), "Invalid condition", fmt.Sprintf("parameter %q: %s", name, err))
+			continue
+		}
+		if !matches {
+			holds = false
+			continue
+		}
+
+		if resolution.Sensitive {
+			triggers = append(triggers, fmt.Sprintf("%q is one of %s", name, quoteJoin(values)))
+		} else {
+			triggers = append(triggers, fmt.Sprintf("%q is %s", name, resolution.Value.String()))
+		}
+	}
+
+	if diags.HasError() {
+		return false, nil, diags
+	}
+	return holds, triggers, diags
+}
+
+// parameterConditionMatches reports whether a resolved value equals one of
+// values, parsed like the parameter's environment variable. List values
+// match when they contain one of values.
+func parameterConditionMatches(resolution parameterResolution, values []string) (bool, error) {
+	elementType := resolution.Type
+	candidates := []attr.Value{resolution.Value}
+
+	switch resolution.Type {
+	case parameterTypeListString, parameterTypeListNumber:
+		elementType = strings.TrimSuffix(strings.TrimPrefix(resolution.Type, "list("), ")")
+		list, ok := resolution.Value.(types.List)
+		if !ok {
+			return false, fmt.Errorf("expected a list value, got %s", resolution.Value.Type(context.Background()))
+		}
+		candidates = list.Elements()
+	case parameterTypeJSON, parameterTypeMapString:
+		return false, fmt.Errorf("conditions cannot reference %s parameters", resolution.Type)
+	}
+
+	for _, raw := range values {
+		for _, candidate := range candidates {
+			matches, err := parameterValueMatches(elementType, resolution.Unit, candidate, raw)
+			if err != nil {
+				return false, err
+			}
+			if matches {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func parameterValueMatches(parameterType string, unit string, candidate attr.Value, raw string) (bool, error) {
+	switch v := candidate.(type) {
+	case types.String:
+		return v.ValueString() == raw, nil
+	case types.Number:
+		expected, err := parseParameterNumber(parameterType, unit, raw)
+		if err != nil {
+			return false, fmt.Errorf("condition value %q is not a valid %s: %s", raw, parameterType, err)
+		}
+		return parameterNumber(v.ValueBigFloat()).Cmp(expected) == 0, nil
+	case types.Bool:
+		expected, diags := parseParameterValue(parameterTypeBool, "", raw, parameterSourceDefault)
+		if diags.HasError() {
+			return false, fmt.Errorf("condition value %q is not a valid bool", raw)
+		}
+		return v.Equal(expected), nil
+	default:
+		return false, fmt.Errorf("unsupported value %s", candidate.Type(context.Background()))
+	}
+}
+
+// resolveHiddenParameterValue resolves a parameter hidden by `visible_when`
+// to its `default`, ignoring any supplied value.
+func resolveHiddenParameterValue(p parameterRef, parameterType string, sources parameterSources, defaultValue types.Dynamic) (resolvedParameterValue, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	// Presets set many parameters at once, so only explicitly supplied
+	// values are worth a warning.
+	_, source, _, lookupDiags := sources.lookup()
+	if !lookupDiags.HasError() && (source == parameterSourceEnvironment || source == parameterSourceContext || source == parameterSourceFile) {
+		diags.AddAttributeWarning(path.Root("visible_when"), "Value ignored", fmt.Sprintf("%s value for parameter %q is ignored because the parameter is hidden; `default` is used instead", parameterSourceLabel(source), p.Name))
+	}
+
+	if defaultValue.IsNull() || defaultValue.IsUnknown() {
+		diags.AddAttributeError(path.Root("default"), "Missing default", fmt.Sprintf("parameter %q is hidden by `visible_when` and has no known `default`", p.Name))
+		return resolvedParameterValue{}, diags
+	}
+
+	value, defaultDiags := resolveParameterDefault(p, parameterType, defaultValue)
+	diags.Append(defaultDiags...)
+	return resolvedParameterValue{Value: value, Source: parameterSourceDefault}, diags
+}
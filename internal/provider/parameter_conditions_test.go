@@ -0,0 +1,113 @@
+// Copyright (c) WANIX Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestParameterConditionMatches(t *testing.T) {
+	testCases := map[string]struct {
+		resolution parameterResolution
+		values     []string
+		want       bool
+	}{
+		"string": {
+			resolution: parameterResolution{Type: parameterTypeString, Value: types.StringValue("gpu.large")},
+			values:     []string{"gpu.small", "gpu.large"},
+			want:       true,
+		},
+		"number compared by value": {
+			resolution: parameterResolution{Type: parameterTypeNumber, Value: types.NumberValue(big.NewFloat(8))},
+			values:     []string{"8.0"},
+			want:       true,
+		},
+		"bytes with units": {
+			resolution: parameterResolution{Type: parameterTypeBytes, Unit: "GiB", Value: types.NumberValue(big.NewFloat(1024))},
+			values:     []string{"1TiB"},
+			want:       true,
+		},
+		"bool": {
+			resolution: parameterResolution{Type: parameterTypeBool, Value: types.BoolValue(true)},
+			values:     []string{"false"},
+			want:       false,
+		},
+		"list contains": {
+			resolution: parameterResolution{Type: parameterTypeListString, Value: types.ListValueMust(types.StringType, []attr.Value{types.StringValue("go"), types.StringValue("cuda")})},
+			values:     []string{"cuda"},
+			want:       true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := parameterConditionMatches(tc.resolution, tc.values)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != tc.want {
+				t.Fatalf("expected %t, got %t", tc.want, got)
+			}
+		})
+	}
+
+	if _, err := parameterConditionMatches(parameterResolution{Type: parameterTypeNumber, Value: types.NumberValue(big.NewFloat(8))}, []string{"eight"}); err == nil {
+		t.Fatalf("expected error for a non-numeric condition value")
+	}
+}
+
+func TestParameterRegistry_EvaluateConditions(t *testing.T) {
+	ctx := context.Background()
+	var registry parameterRegistry
+	registry.resolve("instance_type", parameterResolution{Type: parameterTypeString, Value: types.StringValue("gpu.large")})
+
+	condition := func(parameter string, values ...string) parameterConditionModel {
+		elements := make([]attr.Value, 0, len(values))
+		for _, value := range values {
+			elements = append(elements, types.StringValue(value))
+		}
+		return parameterConditionModel{Parameter: types.StringValue(parameter), Values: types.ListValueMust(types.StringType, elements)}
+	}
+
+	holds, triggers, diags := registry.evaluateParameterConditions(ctx, "required_when", []parameterConditionModel{condition("instance_type", "gpu.large")})
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if !holds || len(triggers) != 1 || !strings.Contains(triggers[0], `"instance_type" is "gpu.large"`) {
+		t.Fatalf("unexpected result %t, %v", holds, triggers)
+	}
+
+	holds, _, diags = registry.evaluateParameterConditions(ctx, "visible_when", []parameterConditionModel{condition("instance_type", "cpu.large")})
+	if diags.HasError() || holds {
+		t.Fatalf("expected condition not to hold, got %t, %v", holds, diags)
+	}
+
+	_, _, diags = registry.evaluateParameterConditions(ctx, "visible_when", []parameterConditionModel{condition("region", "eu")})
+	if !diags.HasError() {
+		t.Fatalf("expected error for a parameter that has not been read")
+	}
+}
+
+func TestResolveHiddenParameterValue(t *testing.T) {
+	envKey := ParameterEnvironmentVariable("gpu_model")
+	t.Setenv(envKey, "h100")
+
+	sources := parameterSources{Name: "gpu_model", EnvKey: envKey}
+	got, diags := resolveHiddenParameterValue(parameterRef{Name: "gpu_model"}, parameterTypeString, sources, types.DynamicValue(types.StringValue("none")))
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if got.Source != parameterSourceDefault || !got.Value.Equal(types.StringValue("none")) {
+		t.Fatalf("expected default value, got %s from %s", got.Value, got.Source)
+	}
+	if diags.WarningsCount() != 1 {
+		t.Fatalf("expected a warning about the ignored value, got %v", diags)
+	}
+}
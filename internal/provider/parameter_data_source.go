@@ -5,33 +5,98 @@ package provider
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"math/big"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	parameterSourceEnvironment = "environment"
+	parameterSourceContext     = "context"
+	parameterSourceFile        = "file"
+	parameterSourcePreset      = "preset"
+	parameterSourceDefault     = "default"
+)
+
+const (
+	parameterMonotonicIncreasing = "increasing"
+	parameterMonotonicDecreasing = "decreasing"
 )
 
 const (
 	parameterTypeString = "string"
 	parameterTypeNumber = "number"
+	parameterTypeBool   = "bool"
+
+	parameterTypeBytes    = "bytes"
+	parameterTypeDuration = "duration"
+
+	parameterTypeJSON      = "json"
+	parameterTypeMapString = "map(string)"
+
+	parameterTypeListString = "list(string)"
+	parameterTypeListNumber = "list(number)"
 )
 
-type parameterDataSource struct{}
+var supportedParameterTypes = []string{
+	parameterTypeString,
+	parameterTypeNumber,
+	parameterTypeBool,
+	parameterTypeBytes,
+	parameterTypeDuration,
+	parameterTypeListString,
+	parameterTypeListNumber,
+	parameterTypeJSON,
+	parameterTypeMapString,
+}
+
+// Ensure parameterDataSource satisfies the data source interfaces.
+var _ datasource.DataSourceWithConfigure = &parameterDataSource{}
+var _ datasource.DataSourceWithValidateConfig = &parameterDataSource{}
+
+// parameterDataSource implements both `manidae_parameter` and
+// `manidae_sensitive_parameter`. Terraform only supports sensitivity per
+// schema attribute, so secrets need their own data source type for `value`
+// to be marked sensitive.
+type parameterDataSource struct {
+	providerData *manidaeProviderData
+	sensitive    bool
+}
 
 type parameterValidationModel struct {
-	Min types.Number `tfsdk:"min"`
-	Max types.Number `tfsdk:"max"`
+	Min        types.Dynamic `tfsdk:"min"`
+	Max        types.Dynamic `tfsdk:"max"`
+	MinItems   types.Int64   `tfsdk:"min_items"`
+	MaxItems   types.Int64   `tfsdk:"max_items"`
+	Regex      types.String  `tfsdk:"regex"`
+	MinLength  types.Int64   `tfsdk:"min_length"`
+	MaxLength  types.Int64   `tfsdk:"max_length"`
+	Error      types.String  `tfsdk:"error"`
+	Monotonic  types.String  `tfsdk:"monotonic"`
+	Step       types.Dynamic `tfsdk:"step"`
+	Integer    types.Bool    `tfsdk:"integer"`
+	JSONSchema types.String  `tfsdk:"json_schema"`
 }
 
 type parameterOptionModel struct {
-	Name  types.String `tfsdk:"name"`
-	Value types.String `tfsdk:"value"`
+	Name        types.String `tfsdk:"name"`
+	Value       types.String `tfsdk:"value"`
+	Description types.String `tfsdk:"description"`
+	Icon        types.String `tfsdk:"icon"`
+	Disabled    types.Bool   `tfsdk:"disabled"`
+	Deprecated  types.String `tfsdk:"deprecated"`
 }
 
 type parameterDataSourceModel struct {
@@ -39,25 +104,69 @@ type parameterDataSourceModel struct {
 	Name                types.String              `tfsdk:"name"`
 	DisplayName         types.String              `tfsdk:"display_name"`
 	Description         types.String              `tfsdk:"description"`
+	Order               types.Int64               `tfsdk:"order"`
+	Group               types.String              `tfsdk:"group"`
+	Icon                types.String              `tfsdk:"icon"`
+	Placeholder         types.String              `tfsdk:"placeholder"`
+	FormType            types.String              `tfsdk:"form_type"`
 	Type                types.String              `tfsdk:"type"`
+	Unit                types.String              `tfsdk:"unit"`
 	Default             types.Dynamic             `tfsdk:"default"`
 	Value               types.Dynamic             `tfsdk:"value"`
+	Sensitive           types.Bool                `tfsdk:"sensitive"`
 	EnvironmentVariable types.String              `tfsdk:"environment_variable"`
+	Mutable             types.Bool                `tfsdk:"mutable"`
+	Ephemeral           types.Bool                `tfsdk:"ephemeral"`
+	IsEphemeral         types.Bool                `tfsdk:"is_ephemeral"`
+	Source              types.String              `tfsdk:"source"`
+	IsDefault           types.Bool                `tfsdk:"is_default"`
+	RawValue            types.String              `tfsdk:"raw_value"`
+	Presets             types.List                `tfsdk:"presets"`
+	Aliases             types.List                `tfsdk:"aliases"`
+	Deprecated          types.String              `tfsdk:"deprecated"`
+	Preset              types.String              `tfsdk:"preset"`
 	Validation          *parameterValidationModel `tfsdk:"validation"`
 	Options             []parameterOptionModel    `tfsdk:"option"`
+	VisibleWhen         []parameterConditionModel `tfsdk:"visible_when"`
+	RequiredWhen        []parameterConditionModel `tfsdk:"required_when"`
+	Visible             types.Bool                `tfsdk:"visible"`
+	SelectedOption      types.Object              `tfsdk:"selected_option"`
 }
 
 func NewParameterDataSource() datasource.DataSource {
 	return &parameterDataSource{}
 }
 
+func NewSensitiveParameterDataSource() datasource.DataSource {
+	return &parameterDataSource{sensitive: true}
+}
+
 func (d *parameterDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	if d.sensitive {
+		resp.TypeName = req.ProviderTypeName + "_sensitive_parameter"
+		return
+	}
 	resp.TypeName = req.ProviderTypeName + "_parameter"
 }
 
 func (d *parameterDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	description := "Reads a parameter value from"
+	valueDescription := "Resolved value (from the environment variable if set, otherwise the parameters file entry keyed by `name`, otherwise the selected preset's entry, otherwise `default`). Secrets belong in `manidae_sensitive_parameter`, whose `value` is marked sensitive."
+	sensitiveAttribute := schema.BoolAttribute{
+		Optional:            true,
+		MarkdownDescription: "Must be `false` or unset: `sensitive = true` is rejected because `value` is not marked sensitive. Declare secrets with `manidae_sensitive_parameter` instead.",
+	}
+	if d.sensitive {
+		description = "Reads a secret parameter like `manidae_parameter`, but marks `value` sensitive, redacts the value from diagnostics and never exports its `default`. Reads the value from"
+		valueDescription = "Resolved value (from the environment variable if set, otherwise the parameters file entry keyed by `name`, otherwise the selected preset's entry, otherwise `default`). Marked sensitive."
+		sensitiveAttribute = schema.BoolAttribute{
+			Computed:            true,
+			MarkdownDescription: "Always `true`.",
+		}
+	}
+
 	resp.Schema = schema.Schema{
-		MarkdownDescription: "Reads a parameter value from an environment variable derived from `name`, falling back to `default`.",
+		MarkdownDescription: description + " an environment variable derived from `name`, then from the provider's parameters file, then from the preset selected by `MANIDAE_PRESET` (or the instance context's `preset`), falling back to `default`. The instance context is the provider's verified context file, or the Manidae API's answer when `endpoint` is configured; it replaces the environment variables.",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Computed:            true,
@@ -75,9 +184,35 @@ func (d *parameterDataSource) Schema(ctx context.Context, req datasource.SchemaR
 				Optional:            true,
 				MarkdownDescription: "Human-friendly description.",
 			},
+			"order": schema.Int64Attribute{
+				Optional:            true,
+				MarkdownDescription: "Position of the parameter in the platform UI; lower values are shown first.",
+			},
+			"group": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Name of the section the platform UI shows the parameter in.",
+			},
+			"icon": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Icon shown next to the parameter in the platform UI, e.g. a URL or a path such as `/icon/memory.svg`.",
+			},
+			"placeholder": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Hint shown in an empty input.",
+			},
+			"form_type": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Control the platform UI renders the parameter with: `input`, `textarea`, `dropdown`, `radio`, `slider`, `checkbox` or `multi-select`. Must suit the `type`: `dropdown`, `radio` and `multi-select` (list types) require `option` blocks, `slider` requires `validation.min` and `validation.max`, and `checkbox` is for `bool` only. Defaults to `checkbox` for `bool`, `textarea` for `json` and `map(string)`, `multi-select` or `dropdown` when options are set, and `input` otherwise.",
+			},
 			"type": schema.StringAttribute{
 				Optional:            true,
-				MarkdownDescription: "Parameter type. Supported values: `string`, `number`. If unset, inferred from `default`.",
+				MarkdownDescription: "Parameter type. Supported values: `string`, `number`, `bool`, `bytes`, `duration`, `list(string)`, `list(number)`, `json`, `map(string)`. If unset, inferred from `default`. List, `json` and `map(string)` values are read from the environment variable as JSON.",
+			},
+			"unit": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Unit `value` is expressed in, for `bytes` and `duration` parameters. Bytes: `B` (default), `KB`, `MB`, `GB`, `TB`, `PB` (powers of 1000) or `KiB`, `MiB`, `GiB`, `TiB`, `PiB` (powers of 1024). Durations: `ms`, `s` (default), `m`, `h`, `d`. Inputs may use any of these units, e.g. `50G`, `50GiB` or `1h30m`; bare numbers are taken to be in `unit`.",
 			},
 			"default": schema.DynamicAttribute{
 				Optional:            true,
@@ -85,29 +220,131 @@ func (d *parameterDataSource) Schema(ctx context.Context, req datasource.SchemaR
 			},
 			"value": schema.DynamicAttribute{
 				Computed:            true,
-				MarkdownDescription: "Resolved value (from environment variable if set, otherwise `default`).",
+				Sensitive:           d.sensitive,
+				MarkdownDescription: valueDescription,
 			},
+			"sensitive": sensitiveAttribute,
 			"environment_variable": schema.StringAttribute{
 				Computed:            true,
-				MarkdownDescription: "Environment variable key used to resolve the value.",
+				MarkdownDescription: "Environment variable key used to resolve the value. The value may instead be read from the file named by `<environment_variable>_FILE`, e.g. a mounted secret.",
+			},
+			"mutable": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Whether the value may change after the instance is created. Defaults to `true`. When `false` and `MANIDAE_ACTION` is not `create`, the value must equal the previous value from `MANIDAE_PARAMETER_PREVIOUS_<sha256(name)>`. With an instance context, the action and previous value come from its `action` and `previous_parameters` instead, and must be present.",
+			},
+			"ephemeral": schema.BoolAttribute{
+				Optional:            true,
+				MarkdownDescription: "Whether the value only applies to a single build. Only the environment variable (or, when in use, the instance context, which is issued per build) is honoured, for the action it was supplied with; the parameters file and presets are ignored, and every other build falls back to `default`, so `default` is required. The value is never compared with a previous one, so `mutable = false` and `validation.monotonic` are not supported.",
+			},
+			"is_ephemeral": schema.BoolAttribute{
+				Computed:            true,
+				MarkdownDescription: "Whether the parameter is ephemeral.",
+			},
+			"source": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Where `value` came from: `environment` (the environment variable or its `_FILE` variant), `context` (the instance context: the provider's verified context file or the Manidae API, which replaces the environment variable), `file` (the provider's parameters file), `preset` (the selected preset) or `default`.",
+			},
+			"is_default": schema.BoolAttribute{
+				Computed:            true,
+				MarkdownDescription: "Whether `value` fell back to `default` because no value was supplied.",
+			},
+			"aliases": schema.ListAttribute{
+				Optional:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "Previous names of the parameter. Their environment variables, parameters file entries and preset entries are consulted after those of `name`, so renaming a parameter keeps existing instances' values. Resolving a value through an alias raises a warning.",
+			},
+			"deprecated": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Marks the parameter as deprecated with a message naming its replacement, e.g. `Use instance_size instead.` A warning is raised whenever a value is supplied for it.",
+			},
+			"selected_option": schema.ObjectAttribute{
+				Computed:            true,
+				AttributeTypes:      selectedOptionAttributeTypes,
+				MarkdownDescription: "The `option` block the value matched, with its `name`, `value`, `description`, `icon` and `deprecated` message. Null when no option matched, for list types, which may select several, and for `manidae_sensitive_parameter`.",
+			},
+			"visible": schema.BoolAttribute{
+				Computed:            true,
+				MarkdownDescription: "Whether every `visible_when` condition holds. Hidden parameters resolve to `default`.",
+			},
+			"presets": schema.ListAttribute{
+				Optional:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "IDs of the `manidae_preset` data sources that may set this parameter, e.g. `[data.manidae_preset.small.id, data.manidae_preset.large.id]`. Referencing them ensures they are read first.",
+			},
+			"preset": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Name of the preset `value` came from, or null when it did not come from a preset.",
+			},
+			"raw_value": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Supplied string before it was parsed, e.g. `\"50GiB\"` for a `bytes` parameter. Null when `value` came from `default`, and always for `manidae_sensitive_parameter`.",
 			},
 		},
 		Blocks: map[string]schema.Block{
 			"validation": schema.SingleNestedBlock{
-				MarkdownDescription: "Numeric validation (only valid when `type = \"number\"`).",
+				MarkdownDescription: "Value validation. `min`/`max`/`step`/`integer` are valid for `number`, `bytes`, `duration` and `list(number)`, `monotonic` for `number`, `bytes` and `duration`, `json_schema` for `json` and `map(string)`, `min_items`/`max_items` for list types, and `regex`/`min_length`/`max_length`/`error` for `string` and `list(string)`.",
 				Attributes: map[string]schema.Attribute{
-					"min": schema.NumberAttribute{
+					"min": schema.DynamicAttribute{
+						Optional:            true,
+						MarkdownDescription: "Minimum allowed value (inclusive). Applies to each element for `list(number)`. For `bytes` and `duration` it may be a string with units, e.g. `\"20GiB\"`.",
+					},
+					"max": schema.DynamicAttribute{
+						Optional:            true,
+						MarkdownDescription: "Maximum allowed value (inclusive). Applies to each element for `list(number)`. For `bytes` and `duration` it may be a string with units, e.g. `\"8h\"`.",
+					},
+					"min_items": schema.Int64Attribute{
+						Optional:            true,
+						MarkdownDescription: "Minimum number of list elements (inclusive).",
+					},
+					"max_items": schema.Int64Attribute{
+						Optional:            true,
+						MarkdownDescription: "Maximum number of list elements (inclusive).",
+					},
+					"regex": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "Regular expression ([RE2 syntax](https://github.com/google/re2/wiki/Syntax)) the value must match. Applies to each element for `list(string)`.",
+					},
+					"min_length": schema.Int64Attribute{
+						Optional:            true,
+						MarkdownDescription: "Minimum string length in characters (inclusive). Applies to each element for `list(string)`.",
+					},
+					"max_length": schema.Int64Attribute{
 						Optional:            true,
-						MarkdownDescription: "Minimum allowed value (inclusive).",
+						MarkdownDescription: "Maximum string length in characters (inclusive). Applies to each element for `list(string)`.",
 					},
-					"max": schema.NumberAttribute{
+					"error": schema.StringAttribute{
 						Optional:            true,
-						MarkdownDescription: "Maximum allowed value (inclusive).",
+						MarkdownDescription: "Custom error message used when a string rule fails. `{name}`, `{value}` and `{rule}` are replaced with the parameter name, the offending value and the failed rule.",
+					},
+					"monotonic": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "Direction the value may change between builds: `increasing` or `decreasing`. The previous value is read from `MANIDAE_PARAMETER_PREVIOUS_<sha256(name)>`, or the instance context's `previous_parameters`; the check is skipped when it is not set.",
+					},
+					"step": schema.DynamicAttribute{
+						Optional:            true,
+						MarkdownDescription: "Increment the value must be a multiple of, counted from `min` (or zero when `min` is unset), e.g. `10` for sizes in steps of 10. Applies to each element for `list(number)`. For `bytes` and `duration` it may be a string with units.",
+					},
+					"integer": schema.BoolAttribute{
+						Optional:            true,
+						MarkdownDescription: "Whether the value must be a whole number. Applies to each element for `list(number)`.",
+					},
+					"json_schema": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "Inline [JSON Schema](https://json-schema.org/) document (draft 2020-12 unless `$schema` says otherwise) the value must match, e.g. `jsonencode({ type = \"object\", required = [\"name\"] })`. References to other documents are not supported.",
 					},
 				},
 			},
+			"visible_when": schema.ListNestedBlock{
+				MarkdownDescription: "Shows the parameter only when another parameter has one of the given values; with several blocks, all must hold. A hidden parameter resolves to `default` even when a value is supplied, so `default` is required.",
+				NestedObject:        parameterConditionBlock(),
+			},
+			"required_when": schema.ListNestedBlock{
+				MarkdownDescription: "Requires a value to be supplied when another parameter has one of the given values; with several blocks, all must hold. Resolving to `default` then fails with a diagnostic naming the triggering parameter.",
+				NestedObject:        parameterConditionBlock(),
+			},
 			"option": schema.ListNestedBlock{
-				MarkdownDescription: "Allowed values (enum) when `type = \"string\"`.",
+				MarkdownDescription: "Allowed values: an enum when `type` is `string`, `number`, `bytes` or `duration`, or a multi-select allow-list checked element by element for list types. Numeric values are compared by value, so `\"8\"` matches `8.0`.",
 				NestedObject: schema.NestedBlockObject{
 					Attributes: map[string]schema.Attribute{
 						"name": schema.StringAttribute{
@@ -116,7 +353,23 @@ func (d *parameterDataSource) Schema(ctx context.Context, req datasource.SchemaR
 						},
 						"value": schema.StringAttribute{
 							Required:            true,
-							MarkdownDescription: "Allowed value.",
+							MarkdownDescription: "Allowed value. Must be a number for `number` and `list(number)`, and may use units for `bytes` and `duration`.",
+						},
+						"description": schema.StringAttribute{
+							Optional:            true,
+							MarkdownDescription: "Longer text shown with the option, e.g. a pricing note.",
+						},
+						"icon": schema.StringAttribute{
+							Optional:            true,
+							MarkdownDescription: "Icon shown next to the option in the platform UI.",
+						},
+						"disabled": schema.BoolAttribute{
+							Optional:            true,
+							MarkdownDescription: "Whether the option is shown but cannot be selected. Selecting it is an error.",
+						},
+						"deprecated": schema.StringAttribute{
+							Optional:            true,
+							MarkdownDescription: "Marks the option as deprecated with a message naming its replacement. Selecting it raises a warning.",
 						},
 					},
 				},
@@ -125,6 +378,147 @@ func (d *parameterDataSource) Schema(ctx context.Context, req datasource.SchemaR
 	}
 }
 
+func parameterConditionBlock() schema.NestedBlockObject {
+	return schema.NestedBlockObject{
+		Attributes: map[string]schema.Attribute{
+			"parameter": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Name of the parameter the condition depends on. Use a reference such as `data.manidae_parameter.instance_type.name` so that parameter is read first.",
+			},
+			"values": schema.ListAttribute{
+				Required:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "Values that satisfy the condition, written like the referenced parameter's environment variable. Numbers are compared by value, and a list parameter matches when it contains one of them.",
+			},
+		},
+	}
+}
+
+func (d *parameterDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*manidaeProviderData)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected Data Source Configure Type", fmt.Sprintf("Expected *manidaeProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData))
+		return
+	}
+
+	d.providerData = providerData
+}
+
+func (d *parameterDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var name, typeAttr, unitAttr, formType types.String
+	var defaultValue types.Dynamic
+	var sensitive, ephemeral, mutable types.Bool
+	var validationObject types.Object
+	var optionList, aliases, visibleWhen, requiredWhen types.List
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("name"), &name)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("aliases"), &aliases)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("visible_when"), &visibleWhen)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("required_when"), &requiredWhen)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("type"), &typeAttr)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("unit"), &unitAttr)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("form_type"), &formType)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("default"), &defaultValue)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("sensitive"), &sensitive)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("ephemeral"), &ephemeral)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("mutable"), &mutable)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("validation"), &validationObject)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("option"), &optionList)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(validateParameterAliases(name, aliases)...)
+	resp.Diagnostics.Append(validateParameterConditions(ctx, name, defaultValue, visibleWhen, requiredWhen)...)
+	if !d.sensitive && sensitive.ValueBool() {
+		resp.Diagnostics.Append(sensitiveParameterError())
+	}
+
+	// Blocks generated by `dynamic` may not be known until Read.
+	if validationObject.IsUnknown() || optionList.IsUnknown() {
+		return
+	}
+	for _, element := range optionList.Elements() {
+		if element.IsUnknown() {
+			return
+		}
+	}
+
+	var validation *parameterValidationModel
+	if !validationObject.IsNull() {
+		validation = &parameterValidationModel{}
+		resp.Diagnostics.Append(validationObject.As(ctx, validation, basetypes.ObjectAsOptions{})...)
+	}
+	var options []parameterOptionModel
+	resp.Diagnostics.Append(optionList.ElementsAs(ctx, &options, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if ephemeral.ValueBool() {
+		resp.Diagnostics.Append(validateEphemeralParameter(defaultValue, mutable, validation)...)
+	}
+
+	if typeAttr.IsUnknown() || (typeAttr.IsNull() && !isWhollyKnown(defaultValue)) {
+		return
+	}
+	parameterType, typeDiags := resolveParameterType(typeAttr, defaultValue)
+	resp.Diagnostics.Append(typeDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if unitAttr.IsUnknown() {
+		return
+	}
+	unit, unitDiags := resolveParameterUnit(parameterType, unitAttr)
+	resp.Diagnostics.Append(unitDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(validateParameterDefinition(parameterType, unit, validation, options)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !formType.IsUnknown() {
+		_, formDiags := resolveParameterFormType(parameterType, formType, validation, options)
+		resp.Diagnostics.Append(formDiags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if defaultValue.IsNull() || !isWhollyKnown(defaultValue) || !parameterDefinitionKnown(validation, options) {
+		return
+	}
+
+	parameter := parameterRef{
+		Name:      name.ValueString(),
+		Sensitive: d.sensitive || sensitive.ValueBool() || sensitive.IsUnknown(),
+		Unit:      unit,
+		Path:      path.Root("default"),
+	}
+	value, defaultDiags := resolveParameterDefault(parameter, parameterType, defaultValue)
+	resp.Diagnostics.Append(defaultDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(validateParameterValue(parameter, parameterType, value, validation, options)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	_, optionDiags := selectParameterOption(parameter, parameterType, value, options)
+	resp.Diagnostics.Append(optionDiags...)
+}
+
 func (d *parameterDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	var data parameterDataSourceModel
 
@@ -133,6 +527,14 @@ func (d *parameterDataSource) Read(ctx context.Context, req datasource.ReadReque
 		return
 	}
 
+	if !d.sensitive && data.Sensitive.ValueBool() {
+		resp.Diagnostics.Append(sensitiveParameterError())
+		return
+	}
+	if d.sensitive {
+		data.Sensitive = types.BoolValue(true)
+	}
+
 	parameterName := data.Name.ValueString()
 	parameterType, typeDiags := resolveParameterType(data.Type, data.Default)
 	resp.Diagnostics.Append(typeDiags...)
@@ -141,21 +543,180 @@ func (d *parameterDataSource) Read(ctx context.Context, req datasource.ReadReque
 	}
 	data.Type = types.StringValue(parameterType)
 
+	unit, unitDiags := resolveParameterUnit(parameterType, data.Unit)
+	resp.Diagnostics.Append(unitDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.Unit = types.StringNull()
+	if unit != "" {
+		data.Unit = types.StringValue(unit)
+	}
+
+	formType, formDiags := resolveParameterFormType(parameterType, data.FormType, data.Validation, data.Options)
+	resp.Diagnostics.Append(formDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.FormType = types.StringValue(formType)
+
+	if d.providerData != nil {
+		resp.Diagnostics.Append(d.providerData.parameters.register(parameterName, parameterDefinition{
+			Type:      parameterType,
+			Unit:      unit,
+			Default:   data.Default,
+			Options:   data.Options,
+			Sensitive: d.sensitive,
+		})...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
 	envKey := ParameterEnvironmentVariable(parameterName)
 	data.EnvironmentVariable = types.StringValue(envKey)
 
-	value, valueDiags := resolveParameterValue(parameterType, envKey, data.Default)
+	if data.Mutable.IsNull() {
+		data.Mutable = types.BoolValue(true)
+	}
+
+	ephemeral := data.Ephemeral.ValueBool()
+	data.IsEphemeral = types.BoolValue(ephemeral)
+	if ephemeral {
+		resp.Diagnostics.Append(validateEphemeralParameter(data.Default, data.Mutable, data.Validation)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	parameter := parameterRef{Name: parameterName, Sensitive: d.sensitive, Unit: unit}
+
+	resp.Diagnostics.Append(validateParameterAliases(data.Name, data.Aliases)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var aliases []string
+	if !data.Aliases.IsNull() {
+		resp.Diagnostics.Append(data.Aliases.ElementsAs(ctx, &aliases, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	sources := parameterSources{Name: parameterName, Aliases: aliases, EnvKey: envKey, Ephemeral: ephemeral, Sensitive: parameter.Sensitive}
+	if d.providerData != nil {
+		instanceContext, contextDiags := d.providerData.instanceContext(ctx)
+		resp.Diagnostics.Append(contextDiags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		sources.Context = instanceContext
+		sources.File = d.providerData.parametersFile
+	}
+
+	presetDiags := d.resolveParameterPreset(ctx, data.Presets, &sources)
+	resp.Diagnostics.Append(presetDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	visible, required, triggers, conditionDiags := d.evaluateParameterConditions(ctx, data.VisibleWhen, data.RequiredWhen)
+	resp.Diagnostics.Append(conditionDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.Visible = types.BoolValue(visible)
+
+	var resolved resolvedParameterValue
+	var valueDiags diag.Diagnostics
+	if visible {
+		resolved, valueDiags = resolveParameterValue(parameterType, unit, sources, data.Default)
+	} else {
+		resolved, valueDiags = resolveHiddenParameterValue(parameter, parameterType, sources, data.Default)
+	}
 	resp.Diagnostics.Append(valueDiags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	resp.Diagnostics.Append(validateParameterValue(parameterType, value, data.Validation, data.Options)...)
+	if required && resolved.Source == parameterSourceDefault {
+		resp.Diagnostics.AddAttributeError(path.Root("required_when"), "Missing value", fmt.Sprintf("parameter %q is required because %s, but %s", parameterName, strings.Join(triggers, " and "), sources.describe()))
+		return
+	}
+	value := resolved.Value
+
+	tflog.Debug(ctx, "Resolved parameter value", map[string]any{
+		"name":   parameterName,
+		"source": resolved.Source,
+		"alias":  resolved.Alias,
+	})
+
+	if resolved.Alias != "" {
+		resp.Diagnostics.AddAttributeWarning(path.Root("aliases"), "Parameter renamed", fmt.Sprintf("%s value for parameter %q was supplied under its old name %q; supply it as %q instead", parameterSourceLabel(resolved.Source), parameterName, resolved.Alias, parameterName))
+	}
+	if !data.Deprecated.IsNull() && resolved.Source != parameterSourceDefault {
+		resp.Diagnostics.AddAttributeWarning(path.Root("deprecated"), "Deprecated parameter", fmt.Sprintf("parameter %q is deprecated: %s", parameterName, data.Deprecated.ValueString()))
+	}
+
+	resp.Diagnostics.Append(validateParameterValue(parameter, parameterType, value, data.Validation, data.Options)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	selectedOption, optionDiags := selectParameterOption(parameter, parameterType, value, data.Options)
+	resp.Diagnostics.Append(optionDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.SelectedOption = selectedOptionValue(selectedOption, parameter.Sensitive)
+
+	// Ephemeral values never carry over between builds, so there is no
+	// previous value to compare against.
+	immutable := !data.Mutable.ValueBool()
+	var action string
+	var previous attr.Value
+	if !ephemeral {
+		var historyDiags diag.Diagnostics
+		action, previous, historyDiags = resolveParameterHistory(sources.Context, immutable, parameterType, unit, append([]string{parameterName}, aliases...)...)
+		resp.Diagnostics.Append(historyDiags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(validateParameterMonotonic(parameter, value, previous, data.Validation)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	if immutable {
+		resp.Diagnostics.Append(validateParameterImmutable(parameter, action, value, previous)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if d.providerData != nil {
+		d.providerData.parameters.resolve(parameterName, parameterResolution{
+			Type:      parameterType,
+			Unit:      unit,
+			Value:     value,
+			Sensitive: parameter.Sensitive,
+		})
+	}
+
 	data.ID = types.StringValue(parameterName)
+	data.Source = types.StringValue(resolved.Source)
+	data.IsDefault = types.BoolValue(resolved.Source == parameterSourceDefault)
+	data.Preset = types.StringNull()
+	if resolved.Source == parameterSourcePreset {
+		data.Preset = types.StringValue(sources.Preset)
+	}
+	data.RawValue = types.StringNull()
+	if resolved.Source != parameterSourceDefault && !parameter.Sensitive {
+		data.RawValue = types.StringValue(resolved.Raw)
+	}
 	data.Value = types.DynamicValue(value)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -165,60 +726,348 @@ func resolveParameterType(typeAttr types.String, defaultValue types.Dynamic) (st
 	var diags diag.Diagnostics
 
 	if typeAttr.IsUnknown() {
-		diags.AddError("Invalid type", "`type` must be known")
+		diags.AddAttributeError(path.Root("type"), "Invalid type", "`type` must be known")
 		return "", diags
 	}
 
 	if !typeAttr.IsNull() {
 		raw := strings.ToLower(strings.TrimSpace(typeAttr.ValueString()))
-		switch raw {
-		case parameterTypeString, parameterTypeNumber:
-			return raw, diags
-		default:
-			diags.AddError("Invalid type", fmt.Sprintf("unsupported `type` %q (supported: %q, %q)", raw, parameterTypeString, parameterTypeNumber))
-			return "", diags
+		for _, supported := range supportedParameterTypes {
+			if raw == supported {
+				return raw, diags
+			}
 		}
+		diags.AddAttributeError(path.Root("type"), "Invalid type", fmt.Sprintf("unsupported `type` %q (supported: %s)", raw, quoteJoin(supportedParameterTypes)))
+		return "", diags
 	}
 
 	if defaultValue.IsUnknown() {
-		diags.AddError("Invalid default", "`default` must be known to infer `type`")
+		diags.AddAttributeError(path.Root("default"), "Invalid default", "`default` must be known to infer `type`")
 		return "", diags
 	}
 
 	if defaultValue.IsNull() {
-		diags.AddError("Missing type", "`type` is required when `default` is not set")
+		diags.AddAttributeError(path.Root("type"), "Missing type", "`type` is required when `default` is not set")
 		return "", diags
 	}
 
-	switch defaultValue.UnderlyingValue().(type) {
+	switch v := defaultValue.UnderlyingValue().(type) {
 	case types.String:
 		return parameterTypeString, diags
 	case types.Number:
 		return parameterTypeNumber, diags
+	case types.Bool:
+		return parameterTypeBool, diags
+	case types.List:
+		switch v.ElementType(context.Background()) {
+		case types.StringType:
+			return parameterTypeListString, diags
+		case types.NumberType:
+			return parameterTypeListNumber, diags
+		}
+	case types.Tuple:
+		return inferListParameterType(v.Elements())
+	case types.Object, types.Map:
+		diags.AddAttributeError(path.Root("type"), "Missing type", fmt.Sprintf("`type` is required when `default` is an object (%q or %q)", parameterTypeJSON, parameterTypeMapString))
+		return "", diags
+	}
+
+	diags.AddAttributeError(path.Root("default"), "Invalid default", "unsupported `default` type (supported: string, number, bool, list of strings, list of numbers)")
+	return "", diags
+}
+
+func inferListParameterType(elements []attr.Value) (string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if len(elements) == 0 {
+		diags.AddAttributeError(path.Root("type"), "Missing type", "`type` is required when `default` is an empty list")
+		return "", diags
+	}
+
+	allStrings, allNumbers := true, true
+	for _, element := range elements {
+		switch element.(type) {
+		case types.String:
+			allNumbers = false
+		case types.Number:
+			allStrings = false
+		default:
+			allStrings, allNumbers = false, false
+		}
+	}
+
+	switch {
+	case allStrings:
+		return parameterTypeListString, diags
+	case allNumbers:
+		return parameterTypeListNumber, diags
 	default:
-		diags.AddError("Invalid default", "unsupported `default` type (supported: string, number)")
+		diags.AddAttributeError(path.Root("default"), "Invalid default", "list `default` must contain only strings or only numbers")
 		return "", diags
 	}
 }
 
-func resolveParameterValue(parameterType string, envKey string, defaultValue types.Dynamic) (attr.Value, diag.Diagnostics) {
+// resolveParameterPreset adds the preset selected by `MANIDAE_PRESET` (or
+// the instance context) to sources when it is one of the parameter's
+// `presets`.
+func (d *parameterDataSource) resolveParameterPreset(ctx context.Context, presetList types.List, sources *parameterSources) diag.Diagnostics {
 	var diags diag.Diagnostics
 
-	rawEnv, hasEnv := os.LookupEnv(envKey)
-	if hasEnv {
-		return parseParameterValue(parameterType, rawEnv, true)
+	selected, selectedBy := selectedPreset(sources.Context)
+	if selected == "" || presetList.IsNull() {
+		return diags
+	}
+
+	var presets []string
+	diags.Append(presetList.ElementsAs(ctx, &presets, false)...)
+	if diags.HasError() || !slices.Contains(presets, selected) {
+		return diags
+	}
+
+	var values map[string]string
+	ok := false
+	if d.providerData != nil {
+		values, ok = d.providerData.presets.lookup(selected)
+	}
+	if !ok {
+		diags.AddAttributeError(path.Root("presets"), "Unknown preset", fmt.Sprintf("preset %q selected by %s has not been read; reference `data.manidae_preset.<name>.id` in `presets`", selected, selectedBy))
+		return diags
+	}
+
+	sources.Preset = selected
+	sources.PresetValues = values
+	return diags
+}
+
+// evaluateParameterConditions evaluates `visible_when` and `required_when`
+// against the parameters already read. A parameter without `visible_when` is
+// always visible; required reports whether `required_when` holds.
+func (d *parameterDataSource) evaluateParameterConditions(ctx context.Context, visibleWhen []parameterConditionModel, requiredWhen []parameterConditionModel) (bool, bool, []string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if len(visibleWhen) == 0 && len(requiredWhen) == 0 {
+		return true, false, nil, diags
+	}
+
+	registry := &parameterRegistry{}
+	if d.providerData != nil {
+		registry = &d.providerData.parameters
+	}
+
+	visible, _, visibleDiags := registry.evaluateParameterConditions(ctx, "visible_when", visibleWhen)
+	diags.Append(visibleDiags...)
+	if diags.HasError() || !visible {
+		return visible, false, nil, diags
+	}
+
+	if len(requiredWhen) == 0 {
+		return true, false, nil, diags
+	}
+	required, triggers, requiredDiags := registry.evaluateParameterConditions(ctx, "required_when", requiredWhen)
+	diags.Append(requiredDiags...)
+	return true, required, triggers, diags
+}
+
+// parameterSources describes where an explicitly supplied value for a
+// parameter may come from. lookup consults them in precedence order: the
+// hashed environment variable (or its `_FILE` variant), then the parameters
+// file, then the selected preset, each under the parameter's name and then
+// its aliases. A verified context file or the control plane's context
+// replaces the environment variables, which any process in the runner could
+// set. Ephemeral parameters skip the parameters file and presets, which
+// persist between builds; the instance context is issued per build, so it
+// still replaces their environment variable.
+type parameterSources struct {
+	Name         string
+	Aliases      []string
+	EnvKey       string
+	Ephemeral    bool
+	Context      *contextFile
+	File         *parametersFile
+	Preset       string
+	PresetValues map[string]string
+	Sensitive    bool
+}
+
+type resolvedParameterValue struct {
+	Value  attr.Value
+	Source string
+	// Raw is the supplied string before parsing; empty for defaults.
+	Raw string
+	// Alias is the old name the value was supplied under, if any.
+	Alias string
+}
+
+// lookup returns the raw value, the source it came from and the name it was
+// supplied under (the parameter's name or one of its aliases), or an empty
+// source when no value was supplied. Within each source the current name
+// takes precedence over aliases.
+func (s parameterSources) lookup() (string, string, string, diag.Diagnostics) {
+	names := append([]string{s.Name}, s.Aliases...)
+
+	if s.Context != nil {
+		for _, name := range names {
+			if raw, ok := s.Context.Parameters[name]; ok {
+				return raw, parameterSourceContext, name, nil
+			}
+		}
+	} else {
+		for i, name := range names {
+			envKey := s.EnvKey
+			if i > 0 {
+				envKey = ParameterEnvironmentVariable(name)
+			}
+			raw, ok, diags := lookupParameterEnvironment(envKey)
+			if diags.HasError() || ok {
+				return raw, parameterSourceEnvironment, name, diags
+			}
+		}
+	}
+
+	if s.Ephemeral {
+		return "", "", "", nil
+	}
+
+	if s.File != nil {
+		for _, name := range names {
+			if raw, ok := s.File.Values[name]; ok {
+				return raw, parameterSourceFile, name, nil
+			}
+		}
+	}
+
+	for _, name := range names {
+		if raw, ok := s.PresetValues[name]; ok {
+			return raw, parameterSourcePreset, name, nil
+		}
+	}
+
+	return "", "", "", nil
+}
+
+// lookupParameterEnvironment reads envKey, or the file named by its `_FILE`
+// variant.
+func lookupParameterEnvironment(envKey string) (string, bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	raw, hasEnv := os.LookupEnv(envKey)
+	secretPath, hasSecretFile := os.LookupEnv(envKey + "_FILE")
+	switch {
+	case hasEnv && hasSecretFile:
+		diags.AddError("Conflicting values", fmt.Sprintf("only one of environment variables %q and %q may be set", envKey, envKey+"_FILE"))
+		return "", false, diags
+	case hasEnv:
+		return raw, true, diags
+	case hasSecretFile:
+		content, err := os.ReadFile(secretPath)
+		if err != nil {
+			diags.AddError("Invalid value", fmt.Sprintf("cannot read the file named by environment variable %q: %s", envKey+"_FILE", err))
+			return "", false, diags
+		}
+		// Mounted secrets usually end with a newline that is not part of the value.
+		return strings.TrimRight(string(content), "\r\n"), true, diags
+	}
+
+	return "", false, diags
+}
+
+func (s parameterSources) describe() string {
+	description := fmt.Sprintf("environment variable %q is not set", s.EnvKey)
+	if s.Context != nil {
+		description = fmt.Sprintf("instance context %q has no %q entry", s.Context.Path, s.Name)
+	}
+	if s.Ephemeral {
+		return description
+	}
+	if s.File != nil {
+		description += fmt.Sprintf(", parameters file %q has no %q entry", s.File.Path, s.Name)
+	}
+	if s.Preset != "" {
+		description += fmt.Sprintf(", preset %q has no %q entry", s.Preset, s.Name)
+	}
+	if s.File != nil || s.Preset != "" {
+		description += ","
+	}
+	return description
+}
+
+func resolveParameterValue(parameterType string, unit string, sources parameterSources, defaultValue types.Dynamic) (resolvedParameterValue, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	raw, source, suppliedAs, lookupDiags := sources.lookup()
+	diags.Append(lookupDiags...)
+	if diags.HasError() {
+		return resolvedParameterValue{}, diags
+	}
+
+	if source != "" {
+		value, parseDiags := parseParameterValue(parameterType, unit, raw, source)
+		if parseDiags.HasError() && sources.Sensitive {
+			diags.AddError("Invalid value", fmt.Sprintf("%s value for sensitive parameter %q cannot be parsed as %s (details are hidden because the parameter is sensitive)", parameterSourceLabel(source), sources.Name, parameterType))
+			return resolvedParameterValue{}, diags
+		}
+		diags.Append(parseDiags...)
+		resolved := resolvedParameterValue{Value: value, Source: source, Raw: raw}
+		if suppliedAs != sources.Name {
+			resolved.Alias = suppliedAs
+		}
+		return resolved, diags
 	}
 
 	if defaultValue.IsUnknown() {
-		diags.AddError("Missing value", fmt.Sprintf("environment variable %q is not set and `default` is unknown", envKey))
-		return nil, diags
+		diags.AddError("Missing value", fmt.Sprintf("%s and `default` is unknown", sources.describe()))
+		return resolvedParameterValue{}, diags
 	}
 
 	if defaultValue.IsNull() {
-		diags.AddError("Missing value", fmt.Sprintf("environment variable %q is not set and `default` is not configured", envKey))
+		diags.AddError("Missing value", fmt.Sprintf("%s and `default` is not configured", sources.describe()))
+		return resolvedParameterValue{}, diags
+	}
+
+	value, defaultDiags := resolveParameterDefault(parameterRef{Name: sources.Name, Sensitive: sources.Sensitive, Unit: unit}, parameterType, defaultValue)
+	diags.Append(defaultDiags...)
+	return resolvedParameterValue{Value: value, Source: parameterSourceDefault}, diags
+}
+
+// resolveParameterDefault resolves a configured `default`, attaching its
+// diagnostics to the `default` attribute and hiding its details when the
+// parameter is sensitive.
+func resolveParameterDefault(p parameterRef, parameterType string, defaultValue types.Dynamic) (attr.Value, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	value, defaultDiags := resolveDefaultParameterValue(parameterType, p.Unit, defaultValue)
+	if defaultDiags.HasError() && p.Sensitive {
+		diags.AddAttributeError(path.Root("default"), "Invalid default", fmt.Sprintf("`default` for sensitive parameter %q is not a valid %s (details are hidden because the parameter is sensitive)", p.Name, parameterType))
 		return nil, diags
 	}
 
+	for _, d := range defaultDiags {
+		if _, ok := d.(diag.DiagnosticWithPath); !ok {
+			d = diag.WithPath(path.Root("default"), d)
+		}
+		diags.Append(d)
+	}
+	return value, diags
+}
+
+func parameterSourceLabel(source string) string {
+	switch source {
+	case parameterSourceEnvironment:
+		return "environment variable"
+	case parameterSourceContext:
+		return "instance context"
+	case parameterSourceFile:
+		return "parameters file"
+	case parameterSourcePreset:
+		return "preset"
+	default:
+		return "`default`"
+	}
+}
+
+func resolveDefaultParameterValue(parameterType string, unit string, defaultValue types.Dynamic) (attr.Value, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
 	underlying := defaultValue.UnderlyingValue()
 	switch parameterType {
 	case parameterTypeString:
@@ -237,7 +1086,7 @@ func resolveParameterValue(parameterType string, envKey string, defaultValue typ
 			diags.AddError("Invalid default", "expected `default` to be a string")
 			return nil, diags
 		}
-	case parameterTypeNumber:
+	case parameterTypeNumber, parameterTypeBytes, parameterTypeDuration:
 		switch v := underlying.(type) {
 		case types.Number:
 			if v.IsUnknown() {
@@ -248,6 +1097,10 @@ func resolveParameterValue(parameterType string, envKey string, defaultValue typ
 				diags.AddError("Invalid default", "`default` must not be null")
 				return nil, diags
 			}
+			if parameterType != parameterTypeNumber && v.ValueBigFloat().Sign() < 0 {
+				diags.AddError("Invalid default", fmt.Sprintf("`default` must not be negative for %s parameters", parameterType))
+				return nil, diags
+			}
 			return v, diags
 		case types.String:
 			if v.IsUnknown() {
@@ -258,18 +1111,52 @@ func resolveParameterValue(parameterType string, envKey string, defaultValue typ
 				diags.AddError("Invalid default", "`default` must not be null")
 				return nil, diags
 			}
-			return parseParameterValue(parameterType, v.ValueString(), false)
+			return parseParameterValue(parameterType, unit, v.ValueString(), parameterSourceDefault)
 		default:
 			diags.AddError("Invalid default", "expected `default` to be a number")
 			return nil, diags
 		}
+	case parameterTypeBool:
+		switch v := underlying.(type) {
+		case types.Bool:
+			if v.IsUnknown() {
+				diags.AddError("Invalid default", "`default` must be known")
+				return nil, diags
+			}
+			if v.IsNull() {
+				diags.AddError("Invalid default", "`default` must not be null")
+				return nil, diags
+			}
+			return v, diags
+		case types.String:
+			if v.IsUnknown() {
+				diags.AddError("Invalid default", "`default` must be known")
+				return nil, diags
+			}
+			if v.IsNull() {
+				diags.AddError("Invalid default", "`default` must not be null")
+				return nil, diags
+			}
+			return parseParameterValue(parameterType, unit, v.ValueString(), parameterSourceDefault)
+		default:
+			diags.AddError("Invalid default", "expected `default` to be a bool")
+			return nil, diags
+		}
+	case parameterTypeListString, parameterTypeListNumber:
+		return resolveListDefaultValue(parameterType, underlying)
+	case parameterTypeJSON, parameterTypeMapString:
+		if !isWhollyKnown(defaultValue) {
+			diags.AddError("Invalid default", "`default` must be known")
+			return nil, diags
+		}
+		return resolveStructuredDefaultValue(parameterType, underlying)
 	default:
 		diags.AddError("Invalid type", fmt.Sprintf("unsupported `type` %q", parameterType))
 		return nil, diags
 	}
 }
 
-func parseParameterValue(parameterType string, raw string, fromEnv bool) (attr.Value, diag.Diagnostics) {
+func parseParameterValue(parameterType string, unit string, raw string, source string) (attr.Value, diag.Diagnostics) {
 	var diags diag.Diagnostics
 
 	switch parameterType {
@@ -278,111 +1165,264 @@ func parseParameterValue(parameterType string, raw string, fromEnv bool) (attr.V
 	case parameterTypeNumber:
 		number, ok := new(big.Float).SetString(strings.TrimSpace(raw))
 		if !ok {
-			source := "`default`"
-			if fromEnv {
-				source = "environment variable"
-			}
-			diags.AddError("Invalid number", fmt.Sprintf("%s value %q cannot be parsed as a number", source, raw))
+			diags.AddError("Invalid number", fmt.Sprintf("%s value %q cannot be parsed as a number", parameterSourceLabel(source), raw))
 			return nil, diags
 		}
 		return types.NumberValue(number), diags
+	case parameterTypeBytes, parameterTypeDuration:
+		number, err := parseParameterNumber(parameterType, unit, raw)
+		if err != nil {
+			diags.AddError(fmt.Sprintf("Invalid %s", parameterType), fmt.Sprintf("%s value %q cannot be parsed as %s: %s", parameterSourceLabel(source), raw, parameterType, err))
+			return nil, diags
+		}
+		return types.NumberValue(new(big.Float).SetPrec(512).SetRat(number)), diags
+	case parameterTypeBool:
+		switch strings.ToLower(strings.TrimSpace(raw)) {
+		case "true", "1", "yes":
+			return types.BoolValue(true), diags
+		case "false", "0", "no":
+			return types.BoolValue(false), diags
+		default:
+			diags.AddError("Invalid bool", fmt.Sprintf("%s value %q cannot be parsed as a bool (expected true/false, 1/0 or yes/no)", parameterSourceLabel(source), raw))
+			return nil, diags
+		}
+	case parameterTypeListString, parameterTypeListNumber:
+		return parseListParameterValue(parameterType, raw, source)
+	case parameterTypeJSON, parameterTypeMapString:
+		return parseStructuredParameterValue(parameterType, raw, source)
 	default:
 		diags.AddError("Invalid type", fmt.Sprintf("unsupported `type` %q", parameterType))
 		return nil, diags
 	}
 }
 
-func validateParameterValue(parameterType string, value attr.Value, validation *parameterValidationModel, options []parameterOptionModel) diag.Diagnostics {
+func resolveListDefaultValue(parameterType string, underlying attr.Value) (attr.Value, diag.Diagnostics) {
 	var diags diag.Diagnostics
 
-	switch parameterType {
-	case parameterTypeString:
-		minSet := validation != nil && !validation.Min.IsNull() && !validation.Min.IsUnknown()
-		maxSet := validation != nil && !validation.Max.IsNull() && !validation.Max.IsUnknown()
-		if minSet || maxSet {
-			diags.AddError("Invalid validation", "`validation` is only supported when `type = \"number\"`")
-			return diags
-		}
+	if underlying.IsUnknown() {
+		diags.AddError("Invalid default", "`default` must be known")
+		return nil, diags
+	}
+	if underlying.IsNull() {
+		diags.AddError("Invalid default", "`default` must not be null")
+		return nil, diags
+	}
 
-		stringValue, ok := value.(types.String)
-		if !ok {
-			diags.AddError("Invalid value", "expected resolved value to be a string")
-			return diags
-		}
-		if stringValue.IsNull() || stringValue.IsUnknown() {
-			diags.AddError("Invalid value", "resolved value must be known")
-			return diags
-		}
+	var elements []attr.Value
+	switch v := underlying.(type) {
+	case types.List:
+		elements = v.Elements()
+	case types.Tuple:
+		elements = v.Elements()
+	case types.String:
+		return parseListParameterValue(parameterType, v.ValueString(), parameterSourceDefault)
+	default:
+		diags.AddError("Invalid default", fmt.Sprintf("expected `default` to be a %s", parameterType))
+		return nil, diags
+	}
 
-		if len(options) == 0 {
-			return diags
+	elementType := listElementParameterType(parameterType)
+	converted := make([]attr.Value, 0, len(elements))
+	for i, element := range elements {
+		if element.IsUnknown() || element.IsNull() {
+			diags.AddError("Invalid default", fmt.Sprintf("`default[%d]` must be known and not null", i))
+			return nil, diags
 		}
 
-		allowed := make(map[string]struct{}, len(options))
-		for i, opt := range options {
-			if opt.Value.IsUnknown() || opt.Value.IsNull() {
-				diags.AddError("Invalid option", fmt.Sprintf("option[%d].value must be set", i))
+		switch v := element.(type) {
+		case types.String:
+			if elementType == parameterTypeString {
+				converted = append(converted, v)
 				continue
 			}
-			allowed[opt.Value.ValueString()] = struct{}{}
-		}
-		if diags.HasError() {
-			return diags
+			number, numberDiags := parseParameterValue(elementType, "", v.ValueString(), parameterSourceDefault)
+			diags.Append(numberDiags...)
+			if diags.HasError() {
+				return nil, diags
+			}
+			converted = append(converted, number)
+		case types.Number:
+			if elementType != parameterTypeNumber {
+				diags.AddError("Invalid default", fmt.Sprintf("`default[%d]` must be a string", i))
+				return nil, diags
+			}
+			converted = append(converted, v)
+		default:
+			diags.AddError("Invalid default", fmt.Sprintf("`default[%d]` must be a %s", i, elementType))
+			return nil, diags
 		}
+	}
 
-		if _, ok := allowed[stringValue.ValueString()]; !ok {
-			diags.AddError("Invalid value", fmt.Sprintf("value %q is not one of the configured options", stringValue.ValueString()))
-		}
+	return newParameterList(parameterType, converted)
+}
 
-		return diags
-	case parameterTypeNumber:
-		if len(options) > 0 {
-			diags.AddError("Invalid option", "`option` blocks are only supported when `type = \"string\"`")
-			return diags
+func parseListParameterValue(parameterType string, raw string, source string) (attr.Value, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	label := parameterSourceLabel(source)
+
+	decoder := json.NewDecoder(strings.NewReader(raw))
+	decoder.UseNumber()
+
+	var decoded []any
+	if err := decoder.Decode(&decoded); err != nil {
+		diags.AddError("Invalid list", fmt.Sprintf("%s value %q cannot be parsed as a JSON array: %s", label, raw, err))
+		return nil, diags
+	}
+
+	elementType := listElementParameterType(parameterType)
+	elements := make([]attr.Value, 0, len(decoded))
+	for i, element := range decoded {
+		switch v := element.(type) {
+		case string:
+			if elementType != parameterTypeString {
+				diags.AddError("Invalid list", fmt.Sprintf("%s element [%d] must be a number, got string %q", label, i, v))
+				return nil, diags
+			}
+			elements = append(elements, types.StringValue(v))
+		case json.Number:
+			if elementType != parameterTypeNumber {
+				diags.AddError("Invalid list", fmt.Sprintf("%s element [%d] must be a string, got number %s", label, i, v))
+				return nil, diags
+			}
+			number, ok := new(big.Float).SetString(v.String())
+			if !ok {
+				diags.AddError("Invalid list", fmt.Sprintf("%s element [%d] value %s cannot be parsed as a number", label, i, v))
+				return nil, diags
+			}
+			elements = append(elements, types.NumberValue(number))
+		default:
+			diags.AddError("Invalid list", fmt.Sprintf("%s element [%d] must be a %s", label, i, elementType))
+			return nil, diags
 		}
+	}
 
-		numberValue, ok := value.(types.Number)
-		if !ok {
-			diags.AddError("Invalid value", "expected resolved value to be a number")
-			return diags
+	return newParameterList(parameterType, elements)
+}
+
+func newParameterList(parameterType string, elements []attr.Value) (attr.Value, diag.Diagnostics) {
+	elementAttrType := attr.Type(types.StringType)
+	if listElementParameterType(parameterType) == parameterTypeNumber {
+		elementAttrType = types.NumberType
+	}
+
+	list, diags := types.ListValue(elementAttrType, elements)
+	if diags.HasError() {
+		return nil, diags
+	}
+	return list, diags
+}
+
+func listElementParameterType(parameterType string) string {
+	if parameterType == parameterTypeListNumber {
+		return parameterTypeNumber
+	}
+	return parameterTypeString
+}
+
+// resolveParameterHistory returns the lowercased action of the current build
+// and the value the parameter (or one of its aliases, in names) resolved to
+// on the previous build. With an instance context both come from its
+// payload and the environment variables are ignored. Because an immutable
+// parameter skips its check when either is missing, the context must then
+// carry `action`, and a previous value for any action other than `create`.
+func resolveParameterHistory(context *contextFile, immutable bool, parameterType string, unit string, names ...string) (string, attr.Value, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if context == nil {
+		action, _ := os.LookupEnv("MANIDAE_ACTION")
+		previousKeys := make([]string, 0, len(names))
+		for _, name := range names {
+			previousKeys = append(previousKeys, ParameterPreviousEnvironmentVariable(name))
 		}
-		if numberValue.IsNull() || numberValue.IsUnknown() {
-			diags.AddError("Invalid value", "resolved value must be known")
-			return diags
+		previous, previousDiags := resolvePreviousParameterValue(parameterType, unit, previousKeys...)
+		diags.Append(previousDiags...)
+		return strings.ToLower(strings.TrimSpace(action)), previous, diags
+	}
+
+	action := strings.ToLower(strings.TrimSpace(context.Payload.Action))
+	previous, previousDiags := context.previousParameterValue(parameterType, unit, names...)
+	diags.Append(previousDiags...)
+	if diags.HasError() || !immutable {
+		return action, previous, diags
+	}
+
+	if action == "" {
+		diags.AddError("Invalid instance context", fmt.Sprintf("instance context %q has no `action`, which immutable parameter %q is checked against", context.Path, names[0]))
+	} else if action != instanceActionCreate && previous == nil {
+		diags.AddError("Invalid instance context", fmt.Sprintf("instance context %q has no `previous_parameters` entry for immutable parameter %q (action %q)", context.Path, names[0], action))
+	}
+	return action, previous, diags
+}
+
+// resolvePreviousParameterValue parses the value the parameter resolved to on
+// the previous build from the first of envKeys that is set. It returns nil
+// when the platform did not supply one.
+func resolvePreviousParameterValue(parameterType string, unit string, envKeys ...string) (attr.Value, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	var envKey, rawPrevious string
+	ok := false
+	for _, envKey = range envKeys {
+		if rawPrevious, ok = os.LookupEnv(envKey); ok {
+			break
 		}
+	}
+	if !ok {
+		return nil, diags
+	}
 
-		val := numberValue.ValueBigFloat()
-		if validation != nil {
-			if validation.Min.IsUnknown() || validation.Max.IsUnknown() {
-				diags.AddError("Invalid validation", "`validation.min` and `validation.max` must be known when set")
-				return diags
-			}
+	previous, parseDiags := parseParameterValue(parameterType, unit, rawPrevious, parameterSourceEnvironment)
+	if parseDiags.HasError() {
+		diags.AddError("Invalid previous value", fmt.Sprintf("environment variable %q does not hold a valid %s value", envKey, parameterType))
+		return nil, diags
+	}
 
-			if !validation.Min.IsNull() && !validation.Max.IsNull() {
-				if validation.Min.ValueBigFloat().Cmp(validation.Max.ValueBigFloat()) > 0 {
-					diags.AddError("Invalid validation", "`validation.min` must be <= `validation.max`")
-					return diags
-				}
-			}
+	return previous, diags
+}
 
-			if !validation.Min.IsNull() {
-				if val.Cmp(validation.Min.ValueBigFloat()) < 0 {
-					diags.AddError("Invalid value", fmt.Sprintf("value %s is less than validation.min %s", val.String(), validation.Min.ValueBigFloat().String()))
-					return diags
-				}
-			}
+// isWhollyKnown reports whether v and every value nested in it are known.
+func isWhollyKnown(v attr.Value) bool {
+	if v.IsUnknown() {
+		return false
+	}
 
-			if !validation.Max.IsNull() {
-				if val.Cmp(validation.Max.ValueBigFloat()) > 0 {
-					diags.AddError("Invalid value", fmt.Sprintf("value %s is greater than validation.max %s", val.String(), validation.Max.ValueBigFloat().String()))
-					return diags
-				}
-			}
+	var nested []attr.Value
+	switch v := v.(type) {
+	case types.Dynamic:
+		if v.IsNull() {
+			return true
+		}
+		if v.IsUnderlyingValueUnknown() {
+			return false
+		}
+		nested = []attr.Value{v.UnderlyingValue()}
+	case types.Tuple:
+		nested = v.Elements()
+	case types.List:
+		nested = v.Elements()
+	case types.Map:
+		for _, element := range v.Elements() {
+			nested = append(nested, element)
 		}
+	case types.Object:
+		for _, attribute := range v.Attributes() {
+			nested = append(nested, attribute)
+		}
+	}
 
-		return diags
-	default:
-		diags.AddError("Invalid type", fmt.Sprintf("unsupported `type` %q", parameterType))
-		return diags
+	for _, element := range nested {
+		if !isWhollyKnown(element) {
+			return false
+		}
+	}
+	return true
+}
+
+func quoteJoin(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = fmt.Sprintf("%q", v)
 	}
+	return strings.Join(quoted, ", ")
 }
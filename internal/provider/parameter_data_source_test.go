@@ -4,10 +4,22 @@
 package provider
 
 import (
+	"context"
 	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 )
 
 func TestParameterEnvironmentVariable(t *testing.T) {
@@ -24,8 +36,8 @@ func TestParameterEnvironmentVariable(t *testing.T) {
 
 func TestValidateParameterValue_NumberMin(t *testing.T) {
 	value := types.NumberValue(new(big.Float).SetInt64(19))
-	diags := validateParameterValue(parameterTypeNumber, value, &parameterValidationModel{
-		Min: types.NumberValue(new(big.Float).SetInt64(20)),
+	diags := validateParameterValue(parameterRef{Name: "test"}, parameterTypeNumber, value, &parameterValidationModel{
+		Min: types.DynamicValue(types.NumberValue(new(big.Float).SetInt64(20))),
 	}, nil)
 
 	if !diags.HasError() {
@@ -35,7 +47,7 @@ func TestValidateParameterValue_NumberMin(t *testing.T) {
 
 func TestValidateParameterValue_StringOptions(t *testing.T) {
 	value := types.StringValue("SA2.MEDIUM8")
-	diags := validateParameterValue(parameterTypeString, value, nil, []parameterOptionModel{
+	diags := validateParameterValue(parameterRef{Name: "test"}, parameterTypeString, value, nil, []parameterOptionModel{
 		{Value: types.StringValue("SA2.MEDIUM2")},
 		{Value: types.StringValue("SA2.MEDIUM4")},
 	})
@@ -44,3 +56,973 @@ func TestValidateParameterValue_StringOptions(t *testing.T) {
 		t.Fatalf("expected options validation error, got none")
 	}
 }
+
+func TestValidateParameterValue_NumberOptions(t *testing.T) {
+	options := []parameterOptionModel{
+		{Value: types.StringValue("2")},
+		{Value: types.StringValue("4")},
+		{Value: types.StringValue("8.0")},
+		{Value: types.StringValue("16")},
+	}
+
+	if diags := validateParameterValue(parameterRef{Name: "cpu"}, parameterTypeNumber, types.NumberValue(big.NewFloat(8)), nil, options); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %#v", diags)
+	}
+
+	diags := validateParameterValue(parameterRef{Name: "cpu"}, parameterTypeNumber, types.NumberValue(big.NewFloat(6)), nil, options)
+	if !diags.HasError() {
+		t.Fatalf("expected options validation error, got none")
+	}
+	if want := "the nearest allowed values are 4 and 8"; !strings.Contains(diags.Errors()[0].Detail(), want) {
+		t.Fatalf("expected detail to contain %q, got %q", want, diags.Errors()[0].Detail())
+	}
+}
+
+func TestValidateParameterValue_NumberStepAndInteger(t *testing.T) {
+	validation := &parameterValidationModel{
+		Min:  types.DynamicValue(types.NumberValue(big.NewFloat(20))),
+		Step: types.DynamicValue(types.NumberValue(big.NewFloat(10))),
+	}
+
+	if diags := validateParameterValue(parameterRef{Name: "disk"}, parameterTypeNumber, types.NumberValue(big.NewFloat(50)), validation, nil); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %#v", diags)
+	}
+
+	diags := validateParameterValue(parameterRef{Name: "disk"}, parameterTypeNumber, types.NumberValue(big.NewFloat(55)), validation, nil)
+	if !diags.HasError() {
+		t.Fatalf("expected step validation error, got none")
+	}
+	if want := "the nearest allowed values are 50 and 60"; !strings.Contains(diags.Errors()[0].Detail(), want) {
+		t.Fatalf("expected detail to contain %q, got %q", want, diags.Errors()[0].Detail())
+	}
+
+	decimal := &parameterValidationModel{Step: types.DynamicValue(types.NumberValue(big.NewFloat(0.1)))}
+	if diags := validateParameterValue(parameterRef{Name: "ratio"}, parameterTypeNumber, types.NumberValue(big.NewFloat(0.3)), decimal, nil); diags.HasError() {
+		t.Fatalf("unexpected diagnostics for decimal step: %#v", diags)
+	}
+
+	integer := &parameterValidationModel{Integer: types.BoolValue(true)}
+	diags = validateParameterValue(parameterRef{Name: "cpu"}, parameterTypeNumber, types.NumberValue(big.NewFloat(2.5)), integer, nil)
+	if !diags.HasError() {
+		t.Fatalf("expected integer validation error, got none")
+	}
+	if want := "the nearest allowed values are 2 and 3"; !strings.Contains(diags.Errors()[0].Detail(), want) {
+		t.Fatalf("expected detail to contain %q, got %q", want, diags.Errors()[0].Detail())
+	}
+}
+
+func TestParseParameterValue_Bool(t *testing.T) {
+	cases := map[string]bool{
+		"true":  true,
+		" YES ": true,
+		"1":     true,
+		"false": false,
+		"No":    false,
+		"0":     false,
+	}
+
+	for raw, want := range cases {
+		got, diags := parseParameterValue(parameterTypeBool, "", raw, parameterSourceEnvironment)
+		if diags.HasError() {
+			t.Fatalf("unexpected diagnostics for %q: %#v", raw, diags)
+		}
+		if !got.Equal(types.BoolValue(want)) {
+			t.Fatalf("expected %t for %q, got %s", want, raw, got)
+		}
+	}
+
+	if _, diags := parseParameterValue(parameterTypeBool, "", "maybe", parameterSourceEnvironment); !diags.HasError() {
+		t.Fatalf("expected parse error, got none")
+	}
+}
+
+func TestParseParameterValue_Units(t *testing.T) {
+	testCases := []struct {
+		parameterType string
+		unit          string
+		raw           string
+		want          string
+	}{
+		{parameterTypeBytes, "GiB", "50GiB", "50"},
+		{parameterTypeBytes, "GiB", "50Gi", "50"},
+		{parameterTypeBytes, "MiB", "51200", "51200"},
+		{parameterTypeBytes, "GiB", "51200 MiB", "50"},
+		{parameterTypeBytes, "MB", "50G", "50000"},
+		{parameterTypeBytes, "B", "1.5k", "1500"},
+		{parameterTypeDuration, "m", "30m", "30"},
+		{parameterTypeDuration, "m", "2h", "120"},
+		{parameterTypeDuration, "s", "1h30m", "5400"},
+		{parameterTypeDuration, "h", "90m", "1.5"},
+		{parameterTypeDuration, "s", "45", "45"},
+	}
+
+	for _, tc := range testCases {
+		got, diags := parseParameterValue(tc.parameterType, tc.unit, tc.raw, parameterSourceEnvironment)
+		if diags.HasError() {
+			t.Fatalf("%s %q: unexpected diagnostics: %#v", tc.parameterType, tc.raw, diags)
+		}
+		number, ok := got.(types.Number)
+		if !ok {
+			t.Fatalf("%s %q: expected a number, got %s", tc.parameterType, tc.raw, got)
+		}
+		if formatted := formatParameterNumber(parameterNumber(number.ValueBigFloat())); formatted != tc.want {
+			t.Fatalf("%s %q: expected %s %s, got %s", tc.parameterType, tc.raw, tc.want, tc.unit, formatted)
+		}
+	}
+
+	for _, raw := range []string{"50 parsecs", "1h30", "fast", "-5"} {
+		if _, diags := parseParameterValue(parameterTypeDuration, "s", raw, parameterSourceEnvironment); !diags.HasError() {
+			t.Fatalf("expected parse error for %q, got none", raw)
+		}
+	}
+
+	if _, diags := parseParameterValue(parameterTypeBytes, "GiB", "-5", parameterSourceEnvironment); !diags.HasError() {
+		t.Fatalf("expected parse error for negative bytes, got none")
+	}
+	if _, diags := parseParameterValue(parameterTypeNumber, "", "-5", parameterSourceEnvironment); diags.HasError() {
+		t.Fatalf("unexpected diagnostics for a negative number: %#v", diags)
+	}
+	if _, diags := resolveDefaultParameterValue(parameterTypeBytes, "GiB", types.DynamicValue(types.NumberValue(big.NewFloat(-5)))); !diags.HasError() {
+		t.Fatalf("expected error for a negative bytes default, got none")
+	}
+}
+
+func TestValidateParameterValue_BytesBoundsWithUnits(t *testing.T) {
+	validation := &parameterValidationModel{
+		Min: types.DynamicValue(types.StringValue("20GiB")),
+		Max: types.DynamicValue(types.NumberValue(big.NewFloat(1024))),
+	}
+	p := parameterRef{Name: "disk", Unit: "GiB"}
+
+	if diags := validateParameterValue(p, parameterTypeBytes, types.NumberValue(big.NewFloat(50)), validation, nil); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %#v", diags)
+	}
+
+	diags := validateParameterValue(p, parameterTypeBytes, types.NumberValue(big.NewFloat(10)), validation, nil)
+	if !diags.HasError() {
+		t.Fatalf("expected min validation error, got none")
+	}
+	if want := "value 10 GiB is less than validation.min 20 GiB"; !strings.Contains(diags.Errors()[0].Detail(), want) {
+		t.Fatalf("expected detail to contain %q, got %q", want, diags.Errors()[0].Detail())
+	}
+
+	if diags := validateParameterDefinition(parameterTypeNumber, "", validation, nil); !diags.HasError() {
+		t.Fatalf("expected units to be rejected for number parameters, got none")
+	}
+}
+
+func TestResolveParameterType_InfersBool(t *testing.T) {
+	got, diags := resolveParameterType(types.StringNull(), types.DynamicValue(types.BoolValue(true)))
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %#v", diags)
+	}
+	if got != parameterTypeBool {
+		t.Fatalf("expected %q, got %q", parameterTypeBool, got)
+	}
+}
+
+func TestValidateParameterValue_BoolRejectsOptions(t *testing.T) {
+	diags := validateParameterValue(parameterRef{Name: "test"}, parameterTypeBool, types.BoolValue(true), nil, []parameterOptionModel{
+		{Value: types.StringValue("true")},
+	})
+
+	if !diags.HasError() {
+		t.Fatalf("expected option error, got none")
+	}
+}
+
+func TestParseParameterValue_List(t *testing.T) {
+	got, diags := parseParameterValue(parameterTypeListNumber, "", `[8080, 9090]`, parameterSourceEnvironment)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %#v", diags)
+	}
+	want := types.ListValueMust(types.NumberType, []attr.Value{
+		types.NumberValue(big.NewFloat(8080)),
+		types.NumberValue(big.NewFloat(9090)),
+	})
+	if !got.Equal(want) {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+
+	if _, diags := parseParameterValue(parameterTypeListString, "", `["a", 1]`, parameterSourceEnvironment); !diags.HasError() {
+		t.Fatalf("expected element type error, got none")
+	}
+}
+
+func TestResolveParameterValue_ListDefault(t *testing.T) {
+	defaultValue := types.DynamicValue(types.TupleValueMust(
+		[]attr.Type{types.StringType, types.StringType},
+		[]attr.Value{types.StringValue("go"), types.StringValue("python")},
+	))
+
+	parameterType, diags := resolveParameterType(types.StringNull(), defaultValue)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %#v", diags)
+	}
+	if parameterType != parameterTypeListString {
+		t.Fatalf("expected %q, got %q", parameterTypeListString, parameterType)
+	}
+
+	got, diags := resolveParameterValue(parameterType, "", parameterSources{EnvKey: "MANIDAE_PARAMETER_TEST_UNSET"}, defaultValue)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %#v", diags)
+	}
+	list, ok := got.Value.(types.List)
+	if !ok || len(list.Elements()) != 2 {
+		t.Fatalf("expected a 2 element list, got %s", got)
+	}
+}
+
+func TestValidateParameterValue_ListOptions(t *testing.T) {
+	value := types.ListValueMust(types.StringType, []attr.Value{
+		types.StringValue("vim"),
+		types.StringValue("emacs"),
+	})
+	diags := validateParameterValue(parameterRef{Name: "test"}, parameterTypeListString, value, nil, []parameterOptionModel{
+		{Value: types.StringValue("vim")},
+		{Value: types.StringValue("vscode")},
+	})
+
+	if !diags.HasError() {
+		t.Fatalf("expected options validation error, got none")
+	}
+}
+
+func TestValidateParameterValue_ListMaxItems(t *testing.T) {
+	value := types.ListValueMust(types.NumberType, []attr.Value{
+		types.NumberValue(big.NewFloat(80)),
+		types.NumberValue(big.NewFloat(443)),
+	})
+	diags := validateParameterValue(parameterRef{Name: "test"}, parameterTypeListNumber, value, &parameterValidationModel{
+		MaxItems: types.Int64Value(1),
+	}, nil)
+
+	if !diags.HasError() {
+		t.Fatalf("expected max_items validation error, got none")
+	}
+}
+
+func TestValidateParameterValue_StringRegex(t *testing.T) {
+	diags := validateParameterValue(parameterRef{Name: "docker_image"}, parameterTypeString, types.StringValue("ubuntu latest"), &parameterValidationModel{
+		Regex: types.StringValue(`^[a-z0-9./:-]+$`),
+		Error: types.StringValue("{name} must be an image reference, got {value}"),
+	}, nil)
+
+	if !diags.HasError() {
+		t.Fatalf("expected regex validation error, got none")
+	}
+
+	errDiag, ok := diags.Errors()[0].(diag.DiagnosticWithPath)
+	if !ok {
+		t.Fatalf("expected diagnostic with path, got %#v", diags.Errors()[0])
+	}
+	if !errDiag.Path().Equal(path.Root("value")) {
+		t.Fatalf("expected diagnostic on %q, got %q", "value", errDiag.Path())
+	}
+	if want := `parameter "docker_image": docker_image must be an image reference, got ubuntu latest`; errDiag.Detail() != want {
+		t.Fatalf("expected detail %q, got %q", want, errDiag.Detail())
+	}
+}
+
+func TestValidateParameterValue_StringLength(t *testing.T) {
+	validation := &parameterValidationModel{
+		MinLength: types.Int64Value(3),
+		MaxLength: types.Int64Value(5),
+	}
+
+	if diags := validateParameterValue(parameterRef{Name: "test"}, parameterTypeString, types.StringValue("abcd"), validation, nil); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %#v", diags)
+	}
+	if diags := validateParameterValue(parameterRef{Name: "test"}, parameterTypeString, types.StringValue("ab"), validation, nil); !diags.HasError() {
+		t.Fatalf("expected min_length validation error, got none")
+	}
+	if diags := validateParameterValue(parameterRef{Name: "test"}, parameterTypeString, types.StringValue("abcdef"), validation, nil); !diags.HasError() {
+		t.Fatalf("expected max_length validation error, got none")
+	}
+}
+
+func TestValidateParameterMonotonic(t *testing.T) {
+	validation := &parameterValidationModel{
+		Monotonic: types.StringValue(parameterMonotonicIncreasing),
+	}
+	previous := types.NumberValue(big.NewFloat(50))
+
+	if diags := validateParameterMonotonic(parameterRef{Name: "root_volume_size_gb"}, types.NumberValue(big.NewFloat(50)), previous, validation); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %#v", diags)
+	}
+	if diags := validateParameterMonotonic(parameterRef{Name: "root_volume_size_gb"}, types.NumberValue(big.NewFloat(40)), previous, validation); !diags.HasError() {
+		t.Fatalf("expected monotonic validation error, got none")
+	}
+	if diags := validateParameterMonotonic(parameterRef{Name: "root_volume_size_gb"}, types.NumberValue(big.NewFloat(40)), nil, validation); diags.HasError() {
+		t.Fatalf("expected no error without previous value, got %#v", diags)
+	}
+}
+
+func TestResolvePreviousParameterValue(t *testing.T) {
+	envKey := ParameterPreviousEnvironmentVariable("root_volume_size_gb")
+	t.Setenv(envKey, "60")
+
+	got, diags := resolvePreviousParameterValue(parameterTypeNumber, "", envKey)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %#v", diags)
+	}
+	if !got.Equal(types.NumberValue(big.NewFloat(60))) {
+		t.Fatalf("expected 60, got %s", got)
+	}
+}
+
+func TestValidateParameterImmutable(t *testing.T) {
+	previous := types.StringValue("eu-west-1")
+	changed := types.StringValue("us-east-1")
+
+	if diags := validateParameterImmutable(parameterRef{Name: "region"}, instanceActionCreate, changed, previous); diags.HasError() {
+		t.Fatalf("unexpected diagnostics on create: %#v", diags)
+	}
+	if diags := validateParameterImmutable(parameterRef{Name: "region"}, "update", previous, previous); diags.HasError() {
+		t.Fatalf("unexpected diagnostics for unchanged value: %#v", diags)
+	}
+	if diags := validateParameterImmutable(parameterRef{Name: "region"}, "update", changed, previous); !diags.HasError() {
+		t.Fatalf("expected immutable parameter error, got none")
+	}
+}
+
+func TestResolveParameterHistory_ContextIgnoresEnvironment(t *testing.T) {
+	// The environment claims nothing changed and names no action; the signed
+	// context must win.
+	t.Setenv("MANIDAE_ACTION", "")
+	t.Setenv(ParameterPreviousEnvironmentVariable("region"), "us-east-1")
+
+	payload := `{"action": "update", "previous_parameters": {"region": "eu-west-1"}}`
+	path := writeContextFile(t, payload, contextAlgorithmHMACSHA256, signHMAC("s3cret"))
+	file, err := loadContextFile(path, contextKey{Algorithm: contextAlgorithmHMACSHA256, HMACSecret: []byte("s3cret")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	action, previous, diags := resolveParameterHistory(file, true, parameterTypeString, "", "region")
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %#v", diags)
+	}
+	if diags := validateParameterImmutable(parameterRef{Name: "region"}, action, types.StringValue("us-east-1"), previous); !diags.HasError() {
+		t.Fatalf("expected immutable parameter error, got none")
+	}
+
+	if _, _, diags := resolveParameterHistory(file, true, parameterTypeString, "", "zone"); !diags.HasError() {
+		t.Fatalf("expected error for missing previous value, got none")
+	}
+	if _, _, diags := resolveParameterHistory(file, false, parameterTypeString, "", "zone"); diags.HasError() {
+		t.Fatalf("unexpected diagnostics for mutable parameter: %#v", diags)
+	}
+
+	file.Payload.Action = ""
+	if _, _, diags := resolveParameterHistory(file, true, parameterTypeString, "", "region"); !diags.HasError() {
+		t.Fatalf("expected error for missing action, got none")
+	}
+}
+
+func TestValidateEphemeralParameter(t *testing.T) {
+	if diags := validateEphemeralParameter(types.DynamicValue(types.BoolValue(false)), types.BoolValue(true), nil); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %#v", diags)
+	}
+	if diags := validateEphemeralParameter(types.DynamicNull(), types.BoolValue(true), nil); !diags.HasError() {
+		t.Fatalf("expected missing default error, got none")
+	}
+	if diags := validateEphemeralParameter(types.DynamicValue(types.BoolValue(false)), types.BoolValue(false), nil); !diags.HasError() {
+		t.Fatalf("expected immutable ephemeral error, got none")
+	}
+}
+
+func TestResolveParameterValue_EphemeralIgnoresPersistentSources(t *testing.T) {
+	envKey := ParameterEnvironmentVariable("reset_home")
+	sources := parameterSources{
+		Name:         "reset_home",
+		EnvKey:       envKey,
+		Ephemeral:    true,
+		File:         &parametersFile{Path: "parameters.json", Values: map[string]string{"reset_home": "true"}},
+		Preset:       "fresh",
+		PresetValues: map[string]string{"reset_home": "true"},
+	}
+	defaultValue := types.DynamicValue(types.BoolValue(false))
+
+	got, diags := resolveParameterValue(parameterTypeBool, "", sources, defaultValue)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %#v", diags)
+	}
+	if got.Source != parameterSourceDefault || !got.Value.Equal(types.BoolValue(false)) {
+		t.Fatalf("expected default, got %s from %s", got.Value, got.Source)
+	}
+
+	t.Setenv(envKey, "true")
+
+	got, diags = resolveParameterValue(parameterTypeBool, "", sources, defaultValue)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %#v", diags)
+	}
+	if got.Source != parameterSourceEnvironment || !got.Value.Equal(types.BoolValue(true)) {
+		t.Fatalf("expected environment value, got %s from %s", got.Value, got.Source)
+	}
+
+	// The instance context is issued per build, so it replaces the
+	// environment variable for ephemeral parameters too.
+	sources.Context = &contextFile{Path: "context.json", Parameters: map[string]string{}}
+
+	got, diags = resolveParameterValue(parameterTypeBool, "", sources, defaultValue)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %#v", diags)
+	}
+	if got.Source != parameterSourceDefault {
+		t.Fatalf("expected default, got %s from %s", got.Value, got.Source)
+	}
+
+	sources.Context.Parameters["reset_home"] = "true"
+
+	got, diags = resolveParameterValue(parameterTypeBool, "", sources, defaultValue)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %#v", diags)
+	}
+	if got.Source != parameterSourceContext || !got.Value.Equal(types.BoolValue(true)) {
+		t.Fatalf("expected context value, got %s from %s", got.Value, got.Source)
+	}
+}
+
+func TestResolveParameterValue_SourcePrecedence(t *testing.T) {
+	envKey := ParameterEnvironmentVariable("instance_type")
+	sources := parameterSources{
+		Name:   "instance_type",
+		EnvKey: envKey,
+		File: &parametersFile{
+			Path:   "parameters.json",
+			Values: map[string]string{"instance_type": "SA2.LARGE8"},
+		},
+	}
+	defaultValue := types.DynamicValue(types.StringValue("SA2.MEDIUM2"))
+
+	got, diags := resolveParameterValue(parameterTypeString, "", sources, defaultValue)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %#v", diags)
+	}
+	if got.Source != parameterSourceFile || !got.Value.Equal(types.StringValue("SA2.LARGE8")) {
+		t.Fatalf("expected file value, got %s from %s", got.Value, got.Source)
+	}
+
+	t.Setenv(envKey, "SA2.MEDIUM4")
+
+	got, diags = resolveParameterValue(parameterTypeString, "", sources, defaultValue)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %#v", diags)
+	}
+	if got.Source != parameterSourceEnvironment || !got.Value.Equal(types.StringValue("SA2.MEDIUM4")) {
+		t.Fatalf("expected environment value, got %s from %s", got.Value, got.Source)
+	}
+	if got.Raw != "SA2.MEDIUM4" {
+		t.Fatalf("expected raw environment value, got %q", got.Raw)
+	}
+
+	sources.File = nil
+	t.Setenv(envKey, "")
+	os.Unsetenv(envKey)
+
+	got, diags = resolveParameterValue(parameterTypeString, "", sources, defaultValue)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %#v", diags)
+	}
+	if got.Source != parameterSourceDefault || got.Raw != "" {
+		t.Fatalf("expected default value without raw value, got %q from %s", got.Raw, got.Source)
+	}
+}
+
+func TestResolveParameterValue_Aliases(t *testing.T) {
+	sources := parameterSources{
+		Name:    "instance_size",
+		Aliases: []string{"instance_type", "machine_type"},
+		EnvKey:  ParameterEnvironmentVariable("instance_size"),
+		File: &parametersFile{
+			Path:   "parameters.json",
+			Values: map[string]string{"instance_type": "SA2.LARGE8"},
+		},
+	}
+	defaultValue := types.DynamicValue(types.StringValue("SA2.MEDIUM2"))
+
+	got, diags := resolveParameterValue(parameterTypeString, "", sources, defaultValue)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %#v", diags)
+	}
+	if got.Source != parameterSourceFile || got.Alias != "instance_type" {
+		t.Fatalf("expected parameters file value under alias, got %s via %q", got.Source, got.Alias)
+	}
+
+	// An alias's environment variable still takes precedence over the file.
+	t.Setenv(ParameterEnvironmentVariable("machine_type"), "SA2.MEDIUM4")
+
+	got, diags = resolveParameterValue(parameterTypeString, "", sources, defaultValue)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %#v", diags)
+	}
+	if got.Source != parameterSourceEnvironment || got.Alias != "machine_type" || !got.Value.Equal(types.StringValue("SA2.MEDIUM4")) {
+		t.Fatalf("expected environment value under alias, got %s from %s via %q", got.Value, got.Source, got.Alias)
+	}
+
+	t.Setenv(sources.EnvKey, "SA2.LARGE4")
+
+	got, diags = resolveParameterValue(parameterTypeString, "", sources, defaultValue)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %#v", diags)
+	}
+	if got.Alias != "" || !got.Value.Equal(types.StringValue("SA2.LARGE4")) {
+		t.Fatalf("expected the current name to take precedence, got %s via %q", got.Value, got.Alias)
+	}
+}
+
+func TestValidateParameterAliases(t *testing.T) {
+	aliases := types.ListValueMust(types.StringType, []attr.Value{
+		types.StringValue("instance_type"),
+		types.StringValue("instance_size"),
+		types.StringValue(""),
+		types.StringValue("instance_type"),
+	})
+
+	diags := validateParameterAliases(types.StringValue("instance_size"), aliases)
+	if diags.ErrorsCount() != 3 {
+		t.Fatalf("expected 3 errors, got %v", diags)
+	}
+	for i, d := range diags {
+		want := path.Root("aliases").AtListIndex(i + 1)
+		if got := d.(diag.DiagnosticWithPath).Path(); !got.Equal(want) {
+			t.Fatalf("expected error at %s, got %s", want, got)
+		}
+	}
+}
+
+func TestValidateParameterValue_SensitiveRedacted(t *testing.T) {
+	diags := validateParameterValue(parameterRef{Name: "api_key", Sensitive: true}, parameterTypeString, types.StringValue("sk-live-123"), &parameterValidationModel{
+		Regex: types.StringValue(`^sk-test-`),
+		Error: types.StringValue("{name} must be a test key, got {value}"),
+	}, nil)
+
+	if !diags.HasError() {
+		t.Fatalf("expected validation error, got none")
+	}
+	for _, d := range diags {
+		if strings.Contains(d.Summary()+d.Detail(), "sk-live-123") {
+			t.Fatalf("diagnostic leaks sensitive value: %s", d.Detail())
+		}
+	}
+
+	// The length of a secret narrows it down as well.
+	diags = validateParameterValue(parameterRef{Name: "api_key", Sensitive: true}, parameterTypeString, types.StringValue("sk-live-123"), &parameterValidationModel{
+		MinLength: types.Int64Value(32),
+	}, nil)
+	if !diags.HasError() {
+		t.Fatalf("expected validation error, got none")
+	}
+	if detail := diags.Errors()[0].Detail(); strings.Contains(detail, "11") {
+		t.Fatalf("diagnostic leaks the length of a sensitive value: %s", detail)
+	}
+	if got := diags.Errors()[0].(diag.DiagnosticWithPath).Path(); !got.Equal(path.Root("value")) {
+		t.Fatalf("expected error at value, got %s", got)
+	}
+}
+
+func TestResolveParameterValue_SensitiveFile(t *testing.T) {
+	envKey := ParameterEnvironmentVariable("api_key")
+	secretPath := filepath.Join(t.TempDir(), "api_key")
+	if err := os.WriteFile(secretPath, []byte("not-a-number\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(envKey+"_FILE", secretPath)
+
+	sources := parameterSources{Name: "api_key", EnvKey: envKey, Sensitive: true}
+
+	got, diags := resolveParameterValue(parameterTypeString, "", sources, types.DynamicNull())
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %#v", diags)
+	}
+	if !got.Value.Equal(types.StringValue("not-a-number")) {
+		t.Fatalf("expected secret file content without trailing newline, got %s", got.Value)
+	}
+
+	_, diags = resolveParameterValue(parameterTypeNumber, "", sources, types.DynamicNull())
+	if !diags.HasError() {
+		t.Fatalf("expected parse error, got none")
+	}
+	for _, d := range diags {
+		if strings.Contains(d.Detail(), "not-a-number") {
+			t.Fatalf("diagnostic leaks sensitive value: %s", d.Detail())
+		}
+	}
+
+	t.Setenv(envKey, "also-set")
+	if _, diags := resolveParameterValue(parameterTypeString, "", sources, types.DynamicNull()); !diags.HasError() {
+		t.Fatalf("expected conflict error, got none")
+	}
+}
+
+func TestParameterDataSourceValidateConfig(t *testing.T) {
+	optionType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"name":        tftypes.String,
+		"value":       tftypes.String,
+		"description": tftypes.String,
+		"icon":        tftypes.String,
+		"disabled":    tftypes.Bool,
+		"deprecated":  tftypes.String,
+	}}
+	option := func(value string) tftypes.Value {
+		return tftypes.NewValue(optionType, nullFilled(optionType, map[string]tftypes.Value{
+			"name":  tftypes.NewValue(tftypes.String, value),
+			"value": tftypes.NewValue(tftypes.String, value),
+		}))
+	}
+
+	testCases := map[string]struct {
+		config   map[string]tftypes.Value
+		wantPath path.Path
+	}{
+		"valid": {
+			config: map[string]tftypes.Value{
+				"type":    tftypes.NewValue(tftypes.String, "string"),
+				"default": tftypes.NewValue(tftypes.String, "small"),
+				"option":  tftypes.NewValue(tftypes.List{ElementType: optionType}, []tftypes.Value{option("small"), option("large")}),
+			},
+		},
+		"unknown default": {
+			config: map[string]tftypes.Value{
+				"type":    tftypes.NewValue(tftypes.String, "number"),
+				"default": tftypes.NewValue(tftypes.DynamicPseudoType, tftypes.UnknownValue),
+			},
+		},
+		"non-numeric option on number": {
+			config: map[string]tftypes.Value{
+				"type":   tftypes.NewValue(tftypes.String, "number"),
+				"option": tftypes.NewValue(tftypes.List{ElementType: optionType}, []tftypes.Value{option("2"), option("two")}),
+			},
+			wantPath: path.Root("option").AtListIndex(1).AtName("value"),
+		},
+		"default off step": {
+			config: map[string]tftypes.Value{
+				"default":    tftypes.NewValue(tftypes.Number, 25),
+				"validation": parameterValidationConfig(t, map[string]tftypes.Value{"step": tftypes.NewValue(tftypes.Number, 10)}),
+			},
+			wantPath: path.Root("default"),
+		},
+		"validation not supported by type": {
+			config: map[string]tftypes.Value{
+				"type":       tftypes.NewValue(tftypes.String, "string"),
+				"validation": parameterValidationConfig(t, map[string]tftypes.Value{"min": tftypes.NewValue(tftypes.Number, 1)}),
+			},
+			wantPath: path.Root("validation").AtName("min"),
+		},
+		"min greater than max": {
+			config: map[string]tftypes.Value{
+				"type": tftypes.NewValue(tftypes.String, "number"),
+				"validation": parameterValidationConfig(t, map[string]tftypes.Value{
+					"min": tftypes.NewValue(tftypes.Number, 10),
+					"max": tftypes.NewValue(tftypes.Number, 1),
+				}),
+			},
+			wantPath: path.Root("validation").AtName("min"),
+		},
+		"slider on string": {
+			config: map[string]tftypes.Value{
+				"type":      tftypes.NewValue(tftypes.String, "string"),
+				"form_type": tftypes.NewValue(tftypes.String, "slider"),
+			},
+			wantPath: path.Root("form_type"),
+		},
+		"slider without bounds": {
+			config: map[string]tftypes.Value{
+				"type":       tftypes.NewValue(tftypes.String, "number"),
+				"form_type":  tftypes.NewValue(tftypes.String, "slider"),
+				"validation": parameterValidationConfig(t, map[string]tftypes.Value{"min": tftypes.NewValue(tftypes.Number, 1)}),
+			},
+			wantPath: path.Root("form_type"),
+		},
+		"slider with bounds": {
+			config: map[string]tftypes.Value{
+				"type":      tftypes.NewValue(tftypes.String, "number"),
+				"form_type": tftypes.NewValue(tftypes.String, "slider"),
+				"validation": parameterValidationConfig(t, map[string]tftypes.Value{
+					"min": tftypes.NewValue(tftypes.Number, 1),
+					"max": tftypes.NewValue(tftypes.Number, 8),
+				}),
+			},
+		},
+		"radio without options": {
+			config: map[string]tftypes.Value{
+				"type":      tftypes.NewValue(tftypes.String, "string"),
+				"form_type": tftypes.NewValue(tftypes.String, "radio"),
+			},
+			wantPath: path.Root("form_type"),
+		},
+		"input with options": {
+			config: map[string]tftypes.Value{
+				"type":      tftypes.NewValue(tftypes.String, "string"),
+				"form_type": tftypes.NewValue(tftypes.String, "input"),
+				"option":    tftypes.NewValue(tftypes.List{ElementType: optionType}, []tftypes.Value{option("small")}),
+			},
+			wantPath: path.Root("form_type"),
+		},
+		"default not among options": {
+			config: map[string]tftypes.Value{
+				"default": tftypes.NewValue(tftypes.String, "medium"),
+				"option":  tftypes.NewValue(tftypes.List{ElementType: optionType}, []tftypes.Value{option("small"), option("large")}),
+			},
+			wantPath: path.Root("default"),
+		},
+		"default outside bounds": {
+			config: map[string]tftypes.Value{
+				"default":    tftypes.NewValue(tftypes.Number, 5),
+				"validation": parameterValidationConfig(t, map[string]tftypes.Value{"min": tftypes.NewValue(tftypes.Number, 10)}),
+			},
+			wantPath: path.Root("default"),
+		},
+		"sensitive on manidae_parameter": {
+			config: map[string]tftypes.Value{
+				"default":   tftypes.NewValue(tftypes.String, "s3cret"),
+				"sensitive": tftypes.NewValue(tftypes.Bool, true),
+			},
+			wantPath: path.Root("sensitive"),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			tc.config["name"] = tftypes.NewValue(tftypes.String, "size")
+
+			resp := &datasource.ValidateConfigResponse{}
+			NewParameterDataSource().(datasource.DataSourceWithValidateConfig).ValidateConfig(context.Background(), datasource.ValidateConfigRequest{
+				Config: parameterConfig(t, tc.config),
+			}, resp)
+
+			if len(tc.wantPath.Steps()) == 0 {
+				if resp.Diagnostics.HasError() {
+					t.Fatalf("unexpected diagnostics: %#v", resp.Diagnostics)
+				}
+				return
+			}
+
+			if len(resp.Diagnostics.Errors()) != 1 {
+				t.Fatalf("expected one error, got %#v", resp.Diagnostics)
+			}
+			errDiag, ok := resp.Diagnostics.Errors()[0].(diag.DiagnosticWithPath)
+			if !ok {
+				t.Fatalf("expected diagnostic with path, got %#v", resp.Diagnostics.Errors()[0])
+			}
+			if !errDiag.Path().Equal(tc.wantPath) {
+				t.Fatalf("expected diagnostic on %q, got %q", tc.wantPath, errDiag.Path())
+			}
+		})
+	}
+}
+
+func TestParameterDataSourceRead(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(testAPIContext))
+	}))
+	defer server.Close()
+
+	fileContext := &contextFile{
+		Payload:            contextPayload{Action: "update"},
+		Parameters:         map[string]string{"region": "ap-south-1"},
+		PreviousParameters: map[string]string{"region": "ap-south-1"},
+	}
+
+	testCases := map[string]struct {
+		dataSource   func() datasource.DataSource
+		providerData func() *manidaeProviderData
+		config       map[string]tftypes.Value
+		wantValue    string
+		wantSource   string
+		wantError    string
+	}{
+		"environment": {
+			providerData: func() *manidaeProviderData { return &manidaeProviderData{} },
+			wantValue:    "eu-central-1",
+			wantSource:   parameterSourceEnvironment,
+		},
+		"file context replaces environment": {
+			providerData: func() *manidaeProviderData { return &manidaeProviderData{context: fileContext} },
+			wantValue:    "ap-south-1",
+			wantSource:   parameterSourceContext,
+		},
+		"API context replaces environment": {
+			providerData: func() *manidaeProviderData {
+				return &manidaeProviderData{client: newTestManidaeClient(t, server, manidaeClientConfig{})}
+			},
+			wantValue:  "eu-west-1",
+			wantSource: parameterSourceContext,
+		},
+		"file context wins over API": {
+			providerData: func() *manidaeProviderData {
+				return &manidaeProviderData{context: fileContext, client: newTestManidaeClient(t, server, manidaeClientConfig{})}
+			},
+			wantValue:  "ap-south-1",
+			wantSource: parameterSourceContext,
+		},
+		"ephemeral from context": {
+			providerData: func() *manidaeProviderData {
+				return &manidaeProviderData{client: newTestManidaeClient(t, server, manidaeClientConfig{})}
+			},
+			config: map[string]tftypes.Value{
+				"ephemeral": tftypes.NewValue(tftypes.Bool, true),
+				"default":   tftypes.NewValue(tftypes.String, "us-east-1"),
+			},
+			wantValue:  "eu-west-1",
+			wantSource: parameterSourceContext,
+		},
+		"immutable parameter changed in API context": {
+			providerData: func() *manidaeProviderData {
+				return &manidaeProviderData{client: newTestManidaeClient(t, server, manidaeClientConfig{})}
+			},
+			config: map[string]tftypes.Value{
+				"mutable": tftypes.NewValue(tftypes.Bool, false),
+			},
+			wantError: "Invalid value",
+		},
+		"sensitive": {
+			dataSource:   NewSensitiveParameterDataSource,
+			providerData: func() *manidaeProviderData { return &manidaeProviderData{context: fileContext} },
+			wantValue:    "ap-south-1",
+			wantSource:   parameterSourceContext,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("MANIDAE_INSTANCE_ID", "7")
+			t.Setenv(ParameterEnvironmentVariable("region"), "eu-central-1")
+
+			newDataSource := testCase.dataSource
+			if newDataSource == nil {
+				newDataSource = NewParameterDataSource
+			}
+			config := map[string]tftypes.Value{
+				"name": tftypes.NewValue(tftypes.String, "region"),
+				"type": tftypes.NewValue(tftypes.String, parameterTypeString),
+			}
+			for attribute, value := range testCase.config {
+				config[attribute] = value
+			}
+
+			got, diags := readParameter(t, newDataSource, testCase.providerData(), config)
+			if testCase.wantError != "" {
+				if diags.ErrorsCount() != 1 || diags.Errors()[0].Summary() != testCase.wantError {
+					t.Fatalf("expected a %q error, got %#v", testCase.wantError, diags)
+				}
+				return
+			}
+			if diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %#v", diags)
+			}
+			if got.Value.UnderlyingValue() != types.StringValue(testCase.wantValue) || got.Source.ValueString() != testCase.wantSource {
+				t.Fatalf("expected %q from %s, got %s from %s", testCase.wantValue, testCase.wantSource, got.Value, got.Source)
+			}
+			if testCase.dataSource != nil && (!got.Sensitive.ValueBool() || !got.RawValue.IsNull()) {
+				t.Fatalf("expected a sensitive value without raw_value, got %#v", got)
+			}
+		})
+	}
+}
+
+func TestParameterDataSourceRead_DuplicateWarning(t *testing.T) {
+	t.Setenv(ParameterEnvironmentVariable("region"), "eu-central-1")
+
+	providerData := &manidaeProviderData{}
+	config := map[string]tftypes.Value{
+		"name": tftypes.NewValue(tftypes.String, "region"),
+		"type": tftypes.NewValue(tftypes.String, parameterTypeString),
+	}
+	if _, diags := readParameter(t, NewParameterDataSource, providerData, config); diags.WarningsCount() != 0 || diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %#v", diags)
+	}
+
+	got, diags := readParameter(t, NewParameterDataSource, providerData, config)
+	if diags.HasError() || diags.WarningsCount() != 1 || diags.Warnings()[0].Summary() != "Duplicate parameter" {
+		t.Fatalf("expected a duplicate parameter warning, got %#v", diags)
+	}
+	if got.Value.UnderlyingValue() != types.StringValue("eu-central-1") {
+		t.Fatalf("unexpected value: %s", got.Value)
+	}
+
+	_, diags = readParameter(t, NewSensitiveParameterDataSource, providerData, config)
+	if diags.ErrorsCount() != 1 || diags.Errors()[0].Summary() != "Conflicting parameter" {
+		t.Fatalf("expected a conflicting parameter error, got %#v", diags)
+	}
+}
+
+// readParameter reads a data source configured with providerData and the
+// given attributes, returning the resulting state.
+func readParameter(t *testing.T, newDataSource func() datasource.DataSource, providerData *manidaeProviderData, values map[string]tftypes.Value) (parameterDataSourceModel, diag.Diagnostics) {
+	t.Helper()
+
+	ctx := context.Background()
+	dataSource := newDataSource()
+	dataSource.(*parameterDataSource).providerData = providerData
+
+	config := dataSourceConfig(t, dataSource, values)
+	resp := datasource.ReadResponse{State: tfsdk.State{Schema: config.Schema, Raw: tftypes.NewValue(config.Raw.Type(), nil)}}
+	dataSource.Read(ctx, datasource.ReadRequest{Config: config}, &resp)
+
+	var got parameterDataSourceModel
+	if !resp.Diagnostics.HasError() {
+		resp.Diagnostics.Append(resp.State.Get(ctx, &got)...)
+	}
+	return got, resp.Diagnostics
+}
+
+// parameterConfig builds a manidae_parameter configuration from the given
+// attributes, leaving everything else null.
+func parameterConfig(t *testing.T, values map[string]tftypes.Value) tfsdk.Config {
+	t.Helper()
+
+	return dataSourceConfig(t, NewParameterDataSource(), values)
+}
+
+// dataSourceConfig builds a configuration of dataSource from the given
+// attributes, leaving everything else null.
+func dataSourceConfig(t *testing.T, dataSource datasource.DataSource, values map[string]tftypes.Value) tfsdk.Config {
+	t.Helper()
+
+	ctx := context.Background()
+	var resp datasource.SchemaResponse
+	dataSource.Schema(ctx, datasource.SchemaRequest{}, &resp)
+
+	objectType, ok := resp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	if !ok {
+		t.Fatalf("unexpected schema type %s", resp.Schema.Type().TerraformType(ctx))
+	}
+
+	return tfsdk.Config{Schema: resp.Schema, Raw: tftypes.NewValue(objectType, nullFilled(objectType, values))}
+}
+
+// parameterValidationConfig builds a `validation` block from the given
+// attributes, leaving everything else null.
+func parameterValidationConfig(t *testing.T, values map[string]tftypes.Value) tftypes.Value {
+	t.Helper()
+
+	ctx := context.Background()
+	var resp datasource.SchemaResponse
+	NewParameterDataSource().Schema(ctx, datasource.SchemaRequest{}, &resp)
+
+	objectType, ok := resp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	if !ok {
+		t.Fatalf("unexpected schema type %s", resp.Schema.Type().TerraformType(ctx))
+	}
+	validationType, ok := objectType.AttributeTypes["validation"].(tftypes.Object)
+	if !ok {
+		t.Fatalf("unexpected validation type %s", objectType.AttributeTypes["validation"])
+	}
+
+	return tftypes.NewValue(validationType, nullFilled(validationType, values))
+}
+
+func nullFilled(objectType tftypes.Object, values map[string]tftypes.Value) map[string]tftypes.Value {
+	filled := make(map[string]tftypes.Value, len(objectType.AttributeTypes))
+	for name, attributeType := range objectType.AttributeTypes {
+		if value, ok := values[name]; ok {
+			filled[name] = value
+			continue
+		}
+		filled[name] = tftypes.NewValue(attributeType, nil)
+	}
+	return filled
+}
@@ -9,6 +9,16 @@ import (
 )
 
 func ParameterEnvironmentVariable(name string) string {
+	return "MANIDAE_PARAMETER_" + parameterNameHash(name)
+}
+
+// ParameterPreviousEnvironmentVariable returns the key the platform uses to
+// pass the value a parameter resolved to on the previous build.
+func ParameterPreviousEnvironmentVariable(name string) string {
+	return "MANIDAE_PARAMETER_PREVIOUS_" + parameterNameHash(name)
+}
+
+func parameterNameHash(name string) string {
 	sum := sha256.Sum256([]byte(name))
-	return "MANIDAE_PARAMETER_" + hex.EncodeToString(sum[:])
+	return hex.EncodeToString(sum[:])
 }
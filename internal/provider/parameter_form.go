@@ -0,0 +1,114 @@
+// Copyright (c) WANIX Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const (
+	parameterFormInput       = "input"
+	parameterFormTextarea    = "textarea"
+	parameterFormDropdown    = "dropdown"
+	parameterFormRadio       = "radio"
+	parameterFormSlider      = "slider"
+	parameterFormCheckbox    = "checkbox"
+	parameterFormMultiSelect = "multi-select"
+)
+
+var supportedParameterFormTypes = []string{
+	parameterFormInput,
+	parameterFormTextarea,
+	parameterFormDropdown,
+	parameterFormRadio,
+	parameterFormSlider,
+	parameterFormCheckbox,
+	parameterFormMultiSelect,
+}
+
+// parameterFormTypeRules describes the parameter types each form type can
+// render, and whether it requires `option` blocks (true) or rejects them
+// (false).
+var parameterFormTypeRules = map[string]struct {
+	Types   []string
+	Options bool
+}{
+	parameterFormInput:       {Types: []string{parameterTypeString, parameterTypeNumber, parameterTypeBytes, parameterTypeDuration, parameterTypeListString, parameterTypeListNumber}},
+	parameterFormTextarea:    {Types: []string{parameterTypeString, parameterTypeJSON, parameterTypeMapString}},
+	parameterFormDropdown:    {Types: []string{parameterTypeString, parameterTypeNumber, parameterTypeBytes, parameterTypeDuration}, Options: true},
+	parameterFormRadio:       {Types: []string{parameterTypeString, parameterTypeNumber, parameterTypeBytes, parameterTypeDuration}, Options: true},
+	parameterFormSlider:      {Types: []string{parameterTypeNumber, parameterTypeBytes, parameterTypeDuration}},
+	parameterFormCheckbox:    {Types: []string{parameterTypeBool}},
+	parameterFormMultiSelect: {Types: []string{parameterTypeListString, parameterTypeListNumber}, Options: true},
+}
+
+// resolveParameterFormType returns the form type the platform UI renders the
+// parameter with, defaulting from the type and options when `form_type` is
+// unset. Combinations the UI cannot render are reported on `form_type`.
+func resolveParameterFormType(parameterType string, formType types.String, validation *parameterValidationModel, options []parameterOptionModel) (string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if formType.IsNull() {
+		return defaultParameterFormType(parameterType, len(options) > 0), diags
+	}
+
+	formPath := path.Root("form_type")
+	resolved := strings.ToLower(strings.TrimSpace(formType.ValueString()))
+	rules, ok := parameterFormTypeRules[resolved]
+	if !ok {
+		diags.AddAttributeError(formPath, "Invalid form type", fmt.Sprintf("unsupported `form_type` %q (supported: %s)", formType.ValueString(), quoteJoin(supportedParameterFormTypes)))
+		return "", diags
+	}
+
+	if !slices.Contains(rules.Types, parameterType) {
+		diags.AddAttributeError(formPath, "Invalid form type", fmt.Sprintf("`form_type = %q` cannot render a %s parameter (supported types: %s)", resolved, parameterType, quoteJoin(rules.Types)))
+		return "", diags
+	}
+
+	switch {
+	case rules.Options && len(options) == 0:
+		diags.AddAttributeError(formPath, "Invalid form type", fmt.Sprintf("`form_type = %q` requires `option` blocks", resolved))
+	case !rules.Options && len(options) > 0:
+		diags.AddAttributeError(formPath, "Invalid form type", fmt.Sprintf("`form_type = %q` cannot render `option` blocks (use %s)", resolved, quoteJoin(parameterFormTypesWithOptions(parameterType))))
+	case resolved == parameterFormSlider && (validation == nil || validation.Min.IsNull() || validation.Max.IsNull()):
+		diags.AddAttributeError(formPath, "Invalid form type", "`form_type = \"slider\"` requires `validation.min` and `validation.max`")
+	}
+	if diags.HasError() {
+		return "", diags
+	}
+
+	return resolved, diags
+}
+
+func defaultParameterFormType(parameterType string, hasOptions bool) string {
+	switch {
+	case parameterType == parameterTypeBool:
+		return parameterFormCheckbox
+	case parameterType == parameterTypeJSON || parameterType == parameterTypeMapString:
+		return parameterFormTextarea
+	case hasOptions && (parameterType == parameterTypeListString || parameterType == parameterTypeListNumber):
+		return parameterFormMultiSelect
+	case hasOptions:
+		return parameterFormDropdown
+	default:
+		return parameterFormInput
+	}
+}
+
+func parameterFormTypesWithOptions(parameterType string) []string {
+	var formTypes []string
+	for _, formType := range supportedParameterFormTypes {
+		rules := parameterFormTypeRules[formType]
+		if rules.Options && slices.Contains(rules.Types, parameterType) {
+			formTypes = append(formTypes, formType)
+		}
+	}
+	return formTypes
+}
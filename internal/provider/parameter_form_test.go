@@ -0,0 +1,37 @@
+// Copyright (c) WANIX Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestResolveParameterFormType_Defaults(t *testing.T) {
+	options := []parameterOptionModel{{Name: types.StringNull(), Value: types.StringValue("go")}}
+
+	testCases := []struct {
+		parameterType string
+		options       []parameterOptionModel
+		want          string
+	}{
+		{parameterTypeString, nil, parameterFormInput},
+		{parameterTypeString, options, parameterFormDropdown},
+		{parameterTypeBool, nil, parameterFormCheckbox},
+		{parameterTypeListString, nil, parameterFormInput},
+		{parameterTypeListString, options, parameterFormMultiSelect},
+		{parameterTypeJSON, nil, parameterFormTextarea},
+	}
+
+	for _, tc := range testCases {
+		got, diags := resolveParameterFormType(tc.parameterType, types.StringNull(), nil, tc.options)
+		if diags.HasError() {
+			t.Fatalf("%s: unexpected diagnostics: %v", tc.parameterType, diags)
+		}
+		if got != tc.want {
+			t.Fatalf("%s with %d options: expected %q, got %q", tc.parameterType, len(tc.options), tc.want, got)
+		}
+	}
+}
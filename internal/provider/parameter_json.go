@@ -0,0 +1,298 @@
+// Copyright (c) WANIX Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// parameterJSONSchemaURL names the inline `validation.json_schema` document
+// when it is compiled. Schemas may not reference other documents.
+const parameterJSONSchemaURL = "urn:manidae:parameter"
+
+// parseStructuredParameterValue decodes a JSON document into the value of a
+// `json` or `map(string)` parameter.
+func parseStructuredParameterValue(parameterType string, raw string, source string) (attr.Value, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	decoded, err := decodeParameterJSON(raw)
+	if err != nil {
+		diags.AddError("Invalid JSON", fmt.Sprintf("%s value %q cannot be parsed as JSON: %s", parameterSourceLabel(source), raw, err))
+		return nil, diags
+	}
+
+	value, err := structuredParameterValue(parameterType, decoded)
+	if err != nil {
+		diags.AddError("Invalid JSON", fmt.Sprintf("%s value %q %s", parameterSourceLabel(source), raw, err))
+		return nil, diags
+	}
+	return value, diags
+}
+
+// resolveStructuredDefaultValue converts an HCL `default` into the value of
+// a `json` or `map(string)` parameter. It goes through the same JSON form as
+// environment values, so both resolve to identically typed values.
+func resolveStructuredDefaultValue(parameterType string, underlying attr.Value) (attr.Value, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	decoded, err := parameterValueToJSON(underlying)
+	if err != nil {
+		diags.AddError("Invalid default", fmt.Sprintf("`default` %s", err))
+		return nil, diags
+	}
+
+	value, err := structuredParameterValue(parameterType, decoded)
+	if err != nil {
+		diags.AddError("Invalid default", fmt.Sprintf("`default` %s", err))
+		return nil, diags
+	}
+	return value, diags
+}
+
+func structuredParameterValue(parameterType string, decoded any) (attr.Value, error) {
+	if parameterType == parameterTypeJSON {
+		return jsonToParameterValue(decoded)
+	}
+
+	object, ok := decoded.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("must be an object of strings")
+	}
+
+	elements := make(map[string]attr.Value, len(object))
+	for key, element := range object {
+		s, ok := element.(string)
+		if !ok {
+			return nil, fmt.Errorf("entry %q must be a string", key)
+		}
+		elements[key] = types.StringValue(s)
+	}
+
+	value, diags := types.MapValue(types.StringType, elements)
+	if diags.HasError() {
+		return nil, diagnosticsError(diags)
+	}
+	return value, nil
+}
+
+// decodeParameterJSON decodes a single JSON document, keeping numbers exact.
+func decodeParameterJSON(raw string) (any, error) {
+	decoder := json.NewDecoder(strings.NewReader(raw))
+	decoder.UseNumber()
+
+	var decoded any
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("unexpected data after the JSON value")
+	}
+	return decoded, nil
+}
+
+// jsonToParameterValue converts decoded JSON into a framework value: objects
+// become objects, arrays become tuples, and null becomes a dynamic null.
+func jsonToParameterValue(decoded any) (attr.Value, error) {
+	ctx := context.Background()
+
+	switch v := decoded.(type) {
+	case nil:
+		return types.DynamicNull(), nil
+	case string:
+		return types.StringValue(v), nil
+	case bool:
+		return types.BoolValue(v), nil
+	case json.Number:
+		number, _, err := big.ParseFloat(v.String(), 10, 512, big.ToNearestEven)
+		if err != nil {
+			return nil, fmt.Errorf("number %s: %w", v, err)
+		}
+		return types.NumberValue(number), nil
+	case []any:
+		elementTypes := make([]attr.Type, 0, len(v))
+		elements := make([]attr.Value, 0, len(v))
+		for _, element := range v {
+			converted, err := jsonToParameterValue(element)
+			if err != nil {
+				return nil, err
+			}
+			elementTypes = append(elementTypes, converted.Type(ctx))
+			elements = append(elements, converted)
+		}
+		tuple, diags := types.TupleValue(elementTypes, elements)
+		if diags.HasError() {
+			return nil, diagnosticsError(diags)
+		}
+		return tuple, nil
+	case map[string]any:
+		attributeTypes := make(map[string]attr.Type, len(v))
+		attributes := make(map[string]attr.Value, len(v))
+		for key, element := range v {
+			converted, err := jsonToParameterValue(element)
+			if err != nil {
+				return nil, err
+			}
+			attributeTypes[key] = converted.Type(ctx)
+			attributes[key] = converted
+		}
+		object, diags := types.ObjectValue(attributeTypes, attributes)
+		if diags.HasError() {
+			return nil, diagnosticsError(diags)
+		}
+		return object, nil
+	default:
+		return nil, fmt.Errorf("unsupported JSON value %T", decoded)
+	}
+}
+
+// parameterValueToJSON converts a framework value into the form produced by
+// decodeParameterJSON, for JSON Schema validation and default normalisation.
+func parameterValueToJSON(value attr.Value) (any, error) {
+	if value == nil || value.IsNull() {
+		return nil, nil
+	}
+	if value.IsUnknown() {
+		return nil, fmt.Errorf("must be known")
+	}
+
+	switch v := value.(type) {
+	case types.Dynamic:
+		if v.IsUnderlyingValueUnknown() {
+			return nil, fmt.Errorf("must be known")
+		}
+		return parameterValueToJSON(v.UnderlyingValue())
+	case types.String:
+		return v.ValueString(), nil
+	case types.Bool:
+		return v.ValueBool(), nil
+	case types.Number:
+		return json.Number(v.ValueBigFloat().Text('g', -1)), nil
+	case types.Tuple:
+		return parameterValuesToJSON(v.Elements())
+	case types.List:
+		return parameterValuesToJSON(v.Elements())
+	case types.Set:
+		return parameterValuesToJSON(v.Elements())
+	case types.Object:
+		return parameterValueMapToJSON(v.Attributes())
+	case types.Map:
+		return parameterValueMapToJSON(v.Elements())
+	default:
+		return nil, fmt.Errorf("has unsupported type %s", value.Type(context.Background()))
+	}
+}
+
+func parameterValuesToJSON(elements []attr.Value) ([]any, error) {
+	converted := make([]any, 0, len(elements))
+	for _, element := range elements {
+		v, err := parameterValueToJSON(element)
+		if err != nil {
+			return nil, err
+		}
+		converted = append(converted, v)
+	}
+	return converted, nil
+}
+
+func parameterValueMapToJSON(elements map[string]attr.Value) (map[string]any, error) {
+	converted := make(map[string]any, len(elements))
+	for key, element := range elements {
+		v, err := parameterValueToJSON(element)
+		if err != nil {
+			return nil, err
+		}
+		converted[key] = v
+	}
+	return converted, nil
+}
+
+// compileParameterJSONSchema compiles an inline `validation.json_schema`
+// document. References to other documents are not resolved.
+func compileParameterJSONSchema(document string) (*jsonschema.Schema, error) {
+	decoded, err := jsonschema.UnmarshalJSON(strings.NewReader(document))
+	if err != nil {
+		return nil, err
+	}
+
+	compiler := jsonschema.NewCompiler()
+	compiler.UseLoader(noParameterSchemaLoader{})
+	if err := compiler.AddResource(parameterJSONSchemaURL, decoded); err != nil {
+		return nil, err
+	}
+	return compiler.Compile(parameterJSONSchemaURL)
+}
+
+// noParameterSchemaLoader refuses to load referenced schema documents, which
+// would otherwise be read from the local filesystem.
+type noParameterSchemaLoader struct{}
+
+func (noParameterSchemaLoader) Load(url string) (any, error) {
+	return nil, fmt.Errorf("cannot load %q: `validation.json_schema` may not reference other documents", url)
+}
+
+// validateParameterJSONSchema checks value against `validation.json_schema`,
+// which validateParameterDefinition has already compiled once.
+func validateParameterJSONSchema(p parameterRef, value attr.Value, validation *parameterValidationModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if validation == nil || validation.JSONSchema.IsNull() {
+		return diags
+	}
+
+	schema, err := compileParameterJSONSchema(validation.JSONSchema.ValueString())
+	if err != nil {
+		diags.AddError("Invalid validation", fmt.Sprintf("`validation.json_schema` is not a valid JSON Schema: %s", err))
+		return diags
+	}
+
+	instance, err := parameterValueToJSON(value)
+	if err != nil {
+		diags.AddError("Invalid value", fmt.Sprintf("resolved value %s", err))
+		return diags
+	}
+
+	err = schema.Validate(instance)
+	if err == nil {
+		return diags
+	}
+
+	if p.Sensitive {
+		// Violations quote the offending values.
+		diags.Append(parameterValueError(p, fmt.Sprintf("%s does not match validation.json_schema", p.label())))
+		return diags
+	}
+
+	var validationErr *jsonschema.ValidationError
+	if !errors.As(err, &validationErr) {
+		diags.Append(parameterValueError(p, fmt.Sprintf("%s does not match validation.json_schema: %s", p.label(), err)))
+		return diags
+	}
+
+	var violations []string
+	for _, unit := range validationErr.BasicOutput().Errors {
+		if unit.Error == nil {
+			continue
+		}
+		location := unit.InstanceLocation
+		if location == "" {
+			location = "/"
+		}
+		violations = append(violations, fmt.Sprintf("at %q: %s", location, unit.Error))
+	}
+	sort.Strings(violations)
+
+	diags.Append(parameterValueError(p, fmt.Sprintf("%s does not match validation.json_schema: %s", p.label(), strings.Join(violations, "; "))))
+	return diags
+}
@@ -0,0 +1,113 @@
+// Copyright (c) WANIX Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestParseParameterValue_Structured(t *testing.T) {
+	value, diags := parseParameterValue(parameterTypeJSON, "", `{"name":"web","ports":[80,443],"tls":null}`, parameterSourceEnvironment)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	object, ok := value.(types.Object)
+	if !ok {
+		t.Fatalf("expected object, got %T", value)
+	}
+	if got := object.Attributes()["name"]; !got.Equal(types.StringValue("web")) {
+		t.Fatalf("unexpected name %s", got)
+	}
+	if got := object.Attributes()["ports"]; len(got.(types.Tuple).Elements()) != 2 {
+		t.Fatalf("unexpected ports %s", got)
+	}
+	if got := object.Attributes()["tls"]; !got.IsNull() {
+		t.Fatalf("expected null tls, got %s", got)
+	}
+
+	value, diags = parseParameterValue(parameterTypeMapString, "", `{"team":"platform"}`, parameterSourceEnvironment)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	expected := types.MapValueMust(types.StringType, map[string]attr.Value{"team": types.StringValue("platform")})
+	if !value.Equal(expected) {
+		t.Fatalf("expected %s, got %s", expected, value)
+	}
+
+	for _, raw := range []string{`{"team":1}`, `["a"]`, `{"team":"a"} {}`} {
+		if _, diags := parseParameterValue(parameterTypeMapString, "", raw, parameterSourceEnvironment); !diags.HasError() {
+			t.Fatalf("expected error for %s", raw)
+		}
+	}
+}
+
+func TestResolveDefaultParameterValue_Structured(t *testing.T) {
+	ctx := context.Background()
+
+	// HCL object defaults resolve to the same value as the equivalent JSON.
+	hclDefault := types.DynamicValue(types.ObjectValueMust(
+		map[string]attr.Type{"replicas": types.NumberType, "tags": types.TupleType{ElemTypes: []attr.Type{types.StringType}}},
+		map[string]attr.Value{
+			"replicas": types.NumberValue(big.NewFloat(3)),
+			"tags":     types.TupleValueMust([]attr.Type{types.StringType}, []attr.Value{types.StringValue("a")}),
+		},
+	))
+	fromDefault, diags := resolveDefaultParameterValue(parameterTypeJSON, "", hclDefault)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	fromEnv, diags := parseParameterValue(parameterTypeJSON, "", `{"replicas":3,"tags":["a"]}`, parameterSourceEnvironment)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if !fromDefault.Type(ctx).Equal(fromEnv.Type(ctx)) || !fromDefault.Equal(fromEnv) {
+		t.Fatalf("expected %s, got %s", fromEnv, fromDefault)
+	}
+}
+
+func TestValidateParameterValue_JSONSchema(t *testing.T) {
+	validation := &parameterValidationModel{
+		JSONSchema: types.StringValue(`{"type":"object","required":["name"],"properties":{"replicas":{"type":"integer","maximum":5}}}`),
+	}
+
+	valid, _ := parseParameterValue(parameterTypeJSON, "", `{"name":"web","replicas":3}`, parameterSourceEnvironment)
+	if diags := validateParameterValue(parameterRef{Name: "service"}, parameterTypeJSON, valid, validation, nil); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	invalid, _ := parseParameterValue(parameterTypeJSON, "", `{"replicas":9}`, parameterSourceEnvironment)
+	diags := validateParameterValue(parameterRef{Name: "service"}, parameterTypeJSON, invalid, validation, nil)
+	if !diags.HasError() {
+		t.Fatalf("expected validation error, got none")
+	}
+	detail := diags[0].Detail()
+	for _, want := range []string{`at "/"`, "name", `at "/replicas"`} {
+		if !strings.Contains(detail, want) {
+			t.Fatalf("expected %q in %q", want, detail)
+		}
+	}
+
+	diags = validateParameterValue(parameterRef{Name: "service", Sensitive: true}, parameterTypeJSON, invalid, validation, nil)
+	if !diags.HasError() {
+		t.Fatalf("expected validation error, got none")
+	}
+	if detail := diags[0].Detail(); strings.Contains(detail, "replicas") || strings.Contains(detail, "9") {
+		t.Fatalf("diagnostic leaks sensitive value: %s", detail)
+	}
+}
+
+func TestValidateParameterDefinition_JSONSchema(t *testing.T) {
+	for _, document := range []string{`{"type":`, `{"$ref":"file:///etc/passwd"}`} {
+		diags := validateParameterDefinition(parameterTypeJSON, "", &parameterValidationModel{JSONSchema: types.StringValue(document)}, nil)
+		if !diags.HasError() {
+			t.Fatalf("expected error for %s", document)
+		}
+	}
+}
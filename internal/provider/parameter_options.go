@@ -0,0 +1,119 @@
+// Copyright (c) WANIX Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// selectedOptionAttributeTypes is the type of the computed `selected_option`
+// attribute.
+var selectedOptionAttributeTypes = map[string]attr.Type{
+	"name":        types.StringType,
+	"value":       types.StringType,
+	"description": types.StringType,
+	"icon":        types.StringType,
+	"deprecated":  types.StringType,
+}
+
+// selectParameterOption finds the options a valid value selected. Selecting a
+// disabled option is an error and a deprecated one a warning. For scalar
+// types it returns the matched option; list types may select several and
+// return nil.
+func selectParameterOption(p parameterRef, parameterType string, value attr.Value, options []parameterOptionModel) (*parameterOptionModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if len(options) == 0 {
+		return nil, diags
+	}
+
+	elementType := parameterType
+	candidates := []attr.Value{value}
+	labels := []string{p.label()}
+	switch parameterType {
+	case parameterTypeListString, parameterTypeListNumber:
+		elementType = parameterTypeString
+		if parameterType == parameterTypeListNumber {
+			elementType = parameterTypeNumber
+		}
+		list, ok := value.(types.List)
+		if !ok {
+			return nil, diags
+		}
+		candidates = list.Elements()
+		labels = make([]string, len(candidates))
+		for i := range candidates {
+			labels[i] = fmt.Sprintf("%s[%d]", p.label(), i)
+		}
+	}
+
+	var selected *parameterOptionModel
+	for i, candidate := range candidates {
+		option := matchParameterOption(elementType, p.Unit, candidate, options)
+		if option == nil {
+			continue
+		}
+		selected = option
+
+		if option.Disabled.ValueBool() {
+			diags.Append(parameterValueError(p, fmt.Sprintf("%s selects option %s, which is disabled", labels[i], p.quote(parameterOptionLabel(option)))))
+			continue
+		}
+		if !option.Deprecated.IsNull() {
+			diags.AddAttributeWarning(p.attributePath(), "Deprecated option", fmt.Sprintf("parameter %q: %s selects option %s, which is deprecated: %s", p.Name, labels[i], p.quote(parameterOptionLabel(option)), option.Deprecated.ValueString()))
+		}
+	}
+
+	if parameterType != elementType {
+		return nil, diags
+	}
+	return selected, diags
+}
+
+// matchParameterOption returns the option equal to value, comparing numbers
+// by value.
+func matchParameterOption(parameterType string, unit string, value attr.Value, options []parameterOptionModel) *parameterOptionModel {
+	for i := range options {
+		option := &options[i]
+		switch v := value.(type) {
+		case types.String:
+			if v.ValueString() == option.Value.ValueString() {
+				return option
+			}
+		case types.Number:
+			expected, err := parseParameterNumber(parameterType, unit, option.Value.ValueString())
+			if err == nil && parameterNumber(v.ValueBigFloat()).Cmp(expected) == 0 {
+				return option
+			}
+		}
+	}
+	return nil
+}
+
+func parameterOptionLabel(option *parameterOptionModel) string {
+	if !option.Name.IsNull() && option.Name.ValueString() != "" {
+		return option.Name.ValueString()
+	}
+	return option.Value.ValueString()
+}
+
+// selectedOptionValue builds `selected_option`, which is null when no single
+// option was selected. Sensitive parameters leave it null too, since the
+// option's `value` and `name` would reveal the secret.
+func selectedOptionValue(option *parameterOptionModel, sensitive bool) types.Object {
+	if option == nil || sensitive {
+		return types.ObjectNull(selectedOptionAttributeTypes)
+	}
+	return types.ObjectValueMust(selectedOptionAttributeTypes, map[string]attr.Value{
+		"name":        option.Name,
+		"value":       option.Value,
+		"description": option.Description,
+		"icon":        option.Icon,
+		"deprecated":  option.Deprecated,
+	})
+}
@@ -0,0 +1,71 @@
+// Copyright (c) WANIX Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func testParameterOption(value string) parameterOptionModel {
+	return parameterOptionModel{
+		Name:        types.StringNull(),
+		Value:       types.StringValue(value),
+		Description: types.StringNull(),
+		Icon:        types.StringNull(),
+		Disabled:    types.BoolNull(),
+		Deprecated:  types.StringNull(),
+	}
+}
+
+func TestSelectParameterOption(t *testing.T) {
+	small := testParameterOption("4")
+	small.Name = types.StringValue("4 vCPU")
+	small.Description = types.StringValue("$0.10/hour")
+	legacy := testParameterOption("6")
+	legacy.Deprecated = types.StringValue("Use 8 instead.")
+	soldOut := testParameterOption("16")
+	soldOut.Disabled = types.BoolValue(true)
+	options := []parameterOptionModel{small, legacy, soldOut}
+
+	p := parameterRef{Name: "cpu_count"}
+
+	selected, diags := selectParameterOption(p, parameterTypeNumber, types.NumberValue(big.NewFloat(4)), options)
+	if len(diags) != 0 {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	got := selectedOptionValue(selected, false)
+	if !got.Attributes()["name"].Equal(types.StringValue("4 vCPU")) || !got.Attributes()["description"].Equal(types.StringValue("$0.10/hour")) {
+		t.Fatalf("unexpected selected option %s", got)
+	}
+
+	_, diags = selectParameterOption(p, parameterTypeNumber, types.NumberValue(big.NewFloat(6)), options)
+	if diags.HasError() || diags.WarningsCount() != 1 || !strings.Contains(diags[0].Detail(), "Use 8 instead.") {
+		t.Fatalf("expected a deprecation warning, got %v", diags)
+	}
+
+	_, diags = selectParameterOption(p, parameterTypeNumber, types.NumberValue(big.NewFloat(16)), options)
+	if !diags.HasError() || !strings.Contains(diags[0].Detail(), "disabled") {
+		t.Fatalf("expected an error for a disabled option, got %v", diags)
+	}
+
+	list := types.ListValueMust(types.NumberType, []attr.Value{types.NumberValue(big.NewFloat(4)), types.NumberValue(big.NewFloat(16))})
+	selected, diags = selectParameterOption(p, parameterTypeListNumber, list, options)
+	if selected != nil || !diags.HasError() || !strings.Contains(diags[0].Detail(), "[1]") {
+		t.Fatalf("expected an error for the disabled element and no selected option, got %v, %v", selected, diags)
+	}
+
+	if got := selectedOptionValue(nil, false); !got.IsNull() {
+		t.Fatalf("expected null selected option, got %s", got)
+	}
+
+	selected, _ = selectParameterOption(p, parameterTypeNumber, types.NumberValue(big.NewFloat(4)), options)
+	if got := selectedOptionValue(selected, true); !got.IsNull() {
+		t.Fatalf("expected null selected option for a sensitive parameter, got %s", got)
+	}
+}
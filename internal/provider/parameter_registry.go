@@ -0,0 +1,105 @@
+// Copyright (c) WANIX Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// parameterDefinition is the part of a `manidae_parameter` block that must
+// agree between blocks sharing a name, since they read the same environment
+// variable.
+type parameterDefinition struct {
+	Type      string
+	Unit      string
+	Default   attr.Value
+	Options   []parameterOptionModel
+	Sensitive bool
+}
+
+// differences lists the fields that differ between two definitions.
+func (d parameterDefinition) differences(other parameterDefinition) []string {
+	var fields []string
+	if d.Type != other.Type {
+		fields = append(fields, fmt.Sprintf("`type` (%q and %q)", other.Type, d.Type))
+	}
+	if d.Unit != other.Unit {
+		fields = append(fields, fmt.Sprintf("`unit` (%q and %q)", other.Unit, d.Unit))
+	}
+	if !d.Default.Equal(other.Default) {
+		fields = append(fields, "`default`")
+	}
+	if !slices.Equal(d.Options, other.Options) {
+		fields = append(fields, "`option` blocks")
+	}
+	if d.Sensitive != other.Sensitive {
+		fields = append(fields, "sensitivity (`manidae_parameter` and `manidae_sensitive_parameter`)")
+	}
+	return fields
+}
+
+// parameterRegistry collects the definition of every parameter read by the
+// provider, so blocks that share a name are detected, and the values they
+// resolved to, for other parameters' conditions. Data sources are read
+// concurrently.
+type parameterRegistry struct {
+	mu          sync.Mutex
+	definitions map[string]parameterDefinition
+	resolutions map[string]parameterResolution
+}
+
+// register records a definition. Conflicting redefinitions are errors and
+// identical ones warnings, both reported against `name`. Terraform does not
+// pass the block address to the provider, so the instances of a block using
+// `count` or `for_each` (or of a module called more than once) cannot be told
+// apart from separate blocks and are warned about too.
+func (r *parameterRegistry) register(name string, definition parameterDefinition) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.definitions[name]
+	if !ok {
+		if r.definitions == nil {
+			r.definitions = make(map[string]parameterDefinition)
+		}
+		r.definitions[name] = definition
+		return diags
+	}
+
+	if differences := definition.differences(existing); len(differences) > 0 {
+		diags.AddAttributeError(path.Root("name"), "Conflicting parameter", fmt.Sprintf("parameter %q is declared more than once with different %s; blocks sharing a name read the same environment variable %q", name, strings.Join(differences, ", "), ParameterEnvironmentVariable(name)))
+		return diags
+	}
+
+	diags.AddAttributeWarning(path.Root("name"), "Duplicate parameter", fmt.Sprintf("parameter %q is declared more than once; the blocks are identical, but only one is needed. Read it once, outside any `count` or `for_each`, and pass its value where it is used", name))
+	return diags
+}
+
+// resolve records the value a parameter resolved to.
+func (r *parameterRegistry) resolve(name string, resolution parameterResolution) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.resolutions == nil {
+		r.resolutions = make(map[string]parameterResolution)
+	}
+	r.resolutions[name] = resolution
+}
+
+func (r *parameterRegistry) lookupResolution(name string) (parameterResolution, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	resolution, ok := r.resolutions[name]
+	return resolution, ok
+}
@@ -0,0 +1,73 @@
+// Copyright (c) WANIX Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"math/big"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestParameterRegistry(t *testing.T) {
+	var registry parameterRegistry
+	definition := parameterDefinition{
+		Type:    parameterTypeNumber,
+		Default: types.DynamicValue(types.NumberValue(big.NewFloat(4))),
+		Options: []parameterOptionModel{{Name: types.StringNull(), Value: types.StringValue("4")}},
+	}
+
+	if diags := registry.register("cpu_count", definition); len(diags) != 0 {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	diags := registry.register("cpu_count", definition)
+	if diags.HasError() || diags.WarningsCount() != 1 {
+		t.Fatalf("expected a single warning for an identical declaration, got %v", diags)
+	}
+	if summary := diags.Warnings()[0].Summary(); summary != "Duplicate parameter" {
+		t.Fatalf("unexpected warning %q", summary)
+	}
+
+	conflicting := definition
+	conflicting.Type = parameterTypeString
+	conflicting.Options = nil
+	diags = registry.register("cpu_count", conflicting)
+	if !diags.HasError() {
+		t.Fatalf("expected error for a conflicting declaration")
+	}
+	detail := diags.Errors()[0].Detail()
+	if !strings.Contains(detail, "`type`") || !strings.Contains(detail, "`option` blocks") || strings.Contains(detail, "`default`") {
+		t.Fatalf("unexpected detail %q", detail)
+	}
+}
+
+func TestParameterRegistry_Concurrent(t *testing.T) {
+	var registry parameterRegistry
+	var wg sync.WaitGroup
+	results := make([]diag.Diagnostics, 8)
+
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = registry.register("region", parameterDefinition{Type: parameterTypeString, Default: types.DynamicNull()})
+		}()
+	}
+	wg.Wait()
+
+	warnings := 0
+	for _, diags := range results {
+		if diags.HasError() {
+			t.Fatalf("unexpected error: %v", diags)
+		}
+		warnings += diags.WarningsCount()
+	}
+	if warnings != len(results)-1 {
+		t.Fatalf("expected %d warnings, got %d", len(results)-1, warnings)
+	}
+}
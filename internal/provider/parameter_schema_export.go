@@ -0,0 +1,459 @@
+// Copyright (c) WANIX Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// ParameterSchema is the machine-readable description of a
+// `manidae_parameter` or `manidae_sensitive_parameter` block emitted by the
+// `schema-export` subcommand.
+//
+// Attributes that are not literal values in the template (for example a
+// `default` referencing a variable) are exported as null.
+type ParameterSchema struct {
+	Name                string                       `json:"name"`
+	DisplayName         *string                      `json:"display_name"`
+	Description         *string                      `json:"description"`
+	Order               *int64                       `json:"order"`
+	Group               *string                      `json:"group"`
+	Icon                *string                      `json:"icon"`
+	Placeholder         *string                      `json:"placeholder"`
+	FormType            *string                      `json:"form_type"`
+	Type                string                       `json:"type"`
+	Unit                *string                      `json:"unit"`
+	Default             json.RawMessage              `json:"default"`
+	Options             []map[string]json.RawMessage `json:"options"`
+	Validation          map[string]json.RawMessage   `json:"validation"`
+	Mutable             bool                         `json:"mutable"`
+	Ephemeral           bool                         `json:"ephemeral"`
+	Sensitive           bool                         `json:"sensitive"`
+	Aliases             []string                     `json:"aliases"`
+	Deprecated          *string                      `json:"deprecated"`
+	VisibleWhen         []map[string]json.RawMessage `json:"visible_when"`
+	RequiredWhen        []map[string]json.RawMessage `json:"required_when"`
+	EnvironmentVariable string                       `json:"environment_variable"`
+}
+
+// parameterDataSourceTypes maps the data source types describing parameters
+// to their implementations.
+var parameterDataSourceTypes = map[string]func() datasource.DataSource{
+	"manidae_parameter":           NewParameterDataSource,
+	"manidae_sensitive_parameter": NewSensitiveParameterDataSource,
+}
+
+// ExportParameterSchemas finds every `data "manidae_parameter"` and
+// `data "manidae_sensitive_parameter"` block in the
+// Terraform files (`.tf` and `.tf.json`) of the module in dir and describes it
+// the way the provider will interpret it at read time. Like Terraform, only
+// the top-level files belong to the module; subdirectories such as nested
+// modules or examples are not read.
+func ExportParameterSchemas(dir string) ([]ParameterSchema, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		if name := entry.Name(); strings.HasSuffix(name, ".tf") || strings.HasSuffix(name, ".tf.json") {
+			files = append(files, filepath.Join(dir, name))
+		}
+	}
+
+	parser := hclparse.NewParser()
+	bodySchemas := make(map[string]*hcl.BodySchema, len(parameterDataSourceTypes))
+	for dataType, newDataSource := range parameterDataSourceTypes {
+		bodySchemas[dataType] = parameterBodySchema(newDataSource())
+	}
+	var blocks []*hcl.Block
+
+	for _, filename := range files {
+		var file *hcl.File
+		var diags hcl.Diagnostics
+		if strings.HasSuffix(filename, ".json") {
+			file, diags = parser.ParseJSONFile(filename)
+		} else {
+			file, diags = parser.ParseHCLFile(filename)
+		}
+		if diags.HasErrors() {
+			return nil, diags
+		}
+
+		content, _, diags := file.Body.PartialContent(&hcl.BodySchema{
+			Blocks: []hcl.BlockHeaderSchema{{Type: "data", LabelNames: []string{"type", "name"}}},
+		})
+		if diags.HasErrors() {
+			return nil, diags
+		}
+
+		for _, block := range content.Blocks {
+			if _, ok := parameterDataSourceTypes[block.Labels[0]]; ok {
+				blocks = append(blocks, block)
+			}
+		}
+	}
+
+	// Conditions usually reference other parameters as
+	// `data.manidae_parameter.<label>.name`, so map addresses such as
+	// `manidae_parameter.<label>` to names first.
+	names := make(map[string]string, len(blocks))
+	for _, block := range blocks {
+		attributes, _ := block.Body.JustAttributes()
+		if name := literalString(attributes, "name"); name != nil {
+			names[block.Labels[0]+"."+block.Labels[1]] = *name
+		}
+	}
+
+	parameters := []ParameterSchema{}
+	for _, block := range blocks {
+		parameter, err := exportParameterBlock(block, bodySchemas[block.Labels[0]], block.Labels[0] == "manidae_sensitive_parameter", names)
+		if err != nil {
+			return nil, fmt.Errorf("%s: data %q %q: %w", block.DefRange, block.Labels[0], block.Labels[1], err)
+		}
+		parameters = append(parameters, parameter)
+	}
+
+	return parameters, nil
+}
+
+// parameterBodySchema derives the HCL schema of a parameter block from the
+// data source schema, so both accept exactly the same arguments.
+func parameterBodySchema(dataSource datasource.DataSource) *hcl.BodySchema {
+	var resp datasource.SchemaResponse
+	dataSource.Schema(context.Background(), datasource.SchemaRequest{}, &resp)
+
+	bodySchema := &hcl.BodySchema{}
+	for name, attribute := range resp.Schema.Attributes {
+		if !attribute.IsRequired() && !attribute.IsOptional() {
+			continue
+		}
+		bodySchema.Attributes = append(bodySchema.Attributes, hcl.AttributeSchema{Name: name, Required: attribute.IsRequired()})
+	}
+	for name := range resp.Schema.Blocks {
+		bodySchema.Blocks = append(bodySchema.Blocks, hcl.BlockHeaderSchema{Type: name})
+	}
+
+	// Terraform meta-arguments.
+	for _, name := range []string{"count", "for_each", "provider", "depends_on"} {
+		bodySchema.Attributes = append(bodySchema.Attributes, hcl.AttributeSchema{Name: name})
+	}
+	bodySchema.Blocks = append(bodySchema.Blocks, hcl.BlockHeaderSchema{Type: "lifecycle"})
+
+	sort.Slice(bodySchema.Attributes, func(i, j int) bool { return bodySchema.Attributes[i].Name < bodySchema.Attributes[j].Name })
+	sort.Slice(bodySchema.Blocks, func(i, j int) bool { return bodySchema.Blocks[i].Type < bodySchema.Blocks[j].Type })
+
+	return bodySchema
+}
+
+func exportParameterBlock(block *hcl.Block, bodySchema *hcl.BodySchema, sensitive bool, names map[string]string) (ParameterSchema, error) {
+	parameter := ParameterSchema{
+		Options:      []map[string]json.RawMessage{},
+		Aliases:      []string{},
+		VisibleWhen:  []map[string]json.RawMessage{},
+		RequiredWhen: []map[string]json.RawMessage{},
+	}
+
+	content, diags := block.Body.Content(bodySchema)
+	if diags.HasErrors() {
+		return parameter, diags
+	}
+
+	name, ok := literalAttribute(content.Attributes, "name")
+	if !ok || name.Type() != cty.String || name.IsNull() {
+		return parameter, fmt.Errorf("`name` must be a literal string")
+	}
+	parameter.Name = name.AsString()
+	parameter.EnvironmentVariable = ParameterEnvironmentVariable(parameter.Name)
+
+	parameter.DisplayName = literalString(content.Attributes, "display_name")
+	parameter.Description = literalString(content.Attributes, "description")
+	parameter.Group = literalString(content.Attributes, "group")
+	parameter.Icon = literalString(content.Attributes, "icon")
+	parameter.Placeholder = literalString(content.Attributes, "placeholder")
+
+	if order, ok := literalAttribute(content.Attributes, "order"); ok && order.Type() == cty.Number && !order.IsNull() {
+		if i, accuracy := order.AsBigFloat().Int64(); accuracy == big.Exact {
+			parameter.Order = &i
+		}
+	}
+
+	parameter.Mutable = literalBool(content.Attributes, "mutable", true)
+	parameter.Ephemeral = literalBool(content.Attributes, "ephemeral", false)
+	// A `sensitive` that is not a literal, e.g. `var.secret`, may be true, so
+	// it is treated as sensitive and the default is never exported.
+	_, sensitiveSet := content.Attributes["sensitive"]
+	parameter.Sensitive = sensitive || sensitiveSet && literalBool(content.Attributes, "sensitive", true)
+	parameter.Deprecated = literalString(content.Attributes, "deprecated")
+
+	if aliases, ok := literalAttribute(content.Attributes, "aliases"); ok && !aliases.IsNull() {
+		if !aliases.CanIterateElements() {
+			return parameter, fmt.Errorf("`aliases` must be a list of strings")
+		}
+		for _, alias := range aliases.AsValueSlice() {
+			if alias.Type() != cty.String || alias.IsNull() {
+				return parameter, fmt.Errorf("`aliases` must be a list of strings")
+			}
+			parameter.Aliases = append(parameter.Aliases, alias.AsString())
+		}
+	}
+
+	typeAttr := types.StringNull()
+	if _, set := content.Attributes["type"]; set {
+		typeValue := literalString(content.Attributes, "type")
+		if typeValue == nil {
+			return parameter, fmt.Errorf("`type` must be a literal string")
+		}
+		typeAttr = types.StringValue(*typeValue)
+	}
+
+	defaultAttr := types.DynamicNull()
+	if defaultValue, ok := literalAttribute(content.Attributes, "default"); ok {
+		converted, err := ctyToAttrValue(defaultValue)
+		if err != nil {
+			return parameter, fmt.Errorf("`default`: %w", err)
+		}
+		defaultAttr = types.DynamicValue(converted)
+
+		// The default of a sensitive parameter is a secret like its value.
+		if !parameter.Sensitive {
+			parameter.Default, err = ctyjson.SimpleJSONValue{Value: defaultValue}.MarshalJSON()
+			if err != nil {
+				return parameter, fmt.Errorf("`default`: %w", err)
+			}
+		}
+	} else if _, set := content.Attributes["default"]; set {
+		defaultAttr = types.DynamicUnknown()
+	}
+
+	parameterType, typeDiags := resolveParameterType(typeAttr, defaultAttr)
+	if typeDiags.HasError() {
+		return parameter, diagnosticsError(typeDiags)
+	}
+	parameter.Type = parameterType
+
+	unitAttr := types.StringNull()
+	if _, set := content.Attributes["unit"]; set {
+		unitValue := literalString(content.Attributes, "unit")
+		if unitValue == nil {
+			return parameter, fmt.Errorf("`unit` must be a literal string")
+		}
+		unitAttr = types.StringValue(*unitValue)
+	}
+	unit, unitDiags := resolveParameterUnit(parameterType, unitAttr)
+	if unitDiags.HasError() {
+		return parameter, diagnosticsError(unitDiags)
+	}
+	if unit != "" {
+		parameter.Unit = &unit
+	}
+
+	for _, nested := range content.Blocks {
+		values, err := literalBlockAttributes(nested)
+		if err != nil {
+			return parameter, fmt.Errorf("%s block: %w", nested.Type, err)
+		}
+
+		switch nested.Type {
+		case "option":
+			parameter.Options = append(parameter.Options, values)
+		case "validation":
+			parameter.Validation = values
+		case "visible_when", "required_when":
+			if err := exportConditionParameter(nested, values, names); err != nil {
+				return parameter, fmt.Errorf("%s block: %w", nested.Type, err)
+			}
+			if nested.Type == "visible_when" {
+				parameter.VisibleWhen = append(parameter.VisibleWhen, values)
+			} else {
+				parameter.RequiredWhen = append(parameter.RequiredWhen, values)
+			}
+		}
+	}
+
+	if _, set := content.Attributes["form_type"]; set {
+		parameter.FormType = literalString(content.Attributes, "form_type")
+	} else {
+		formType := defaultParameterFormType(parameterType, len(parameter.Options) > 0)
+		parameter.FormType = &formType
+	}
+
+	return parameter, nil
+}
+
+// exportConditionParameter resolves a condition's `parameter` written as a
+// reference to another parameter block's `name`.
+func exportConditionParameter(block *hcl.Block, values map[string]json.RawMessage, names map[string]string) error {
+	if string(values["parameter"]) != "null" {
+		return nil
+	}
+
+	attributes, _ := block.Body.JustAttributes()
+	attribute, ok := attributes["parameter"]
+	if !ok {
+		return nil
+	}
+	traversal, diags := hcl.AbsTraversalForExpr(attribute.Expr)
+	if diags.HasErrors() || len(traversal) != 4 || traversal.RootName() != "data" {
+		return nil
+	}
+
+	dataType, typeOK := traversal[1].(hcl.TraverseAttr)
+	label, labelOK := traversal[2].(hcl.TraverseAttr)
+	attributeName, attributeOK := traversal[3].(hcl.TraverseAttr)
+	if !typeOK || !labelOK || !attributeOK || attributeName.Name != "name" {
+		return nil
+	}
+
+	name, ok := names[dataType.Name+"."+label.Name]
+	if !ok {
+		return nil
+	}
+	encoded, err := json.Marshal(name)
+	if err != nil {
+		return err
+	}
+	values["parameter"] = encoded
+	return nil
+}
+
+// literalAttribute evaluates an attribute without an evaluation context. It
+// reports false when the attribute is unset or is not a literal value.
+func literalAttribute(attributes hcl.Attributes, name string) (cty.Value, bool) {
+	attribute, ok := attributes[name]
+	if !ok {
+		return cty.NilVal, false
+	}
+
+	value, diags := attribute.Expr.Value(nil)
+	if diags.HasErrors() || !value.IsWhollyKnown() {
+		return cty.NilVal, false
+	}
+	return value, true
+}
+
+func literalString(attributes hcl.Attributes, name string) *string {
+	value, ok := literalAttribute(attributes, name)
+	if !ok || value.Type() != cty.String || value.IsNull() {
+		return nil
+	}
+	s := value.AsString()
+	return &s
+}
+
+func literalBool(attributes hcl.Attributes, name string, fallback bool) bool {
+	value, ok := literalAttribute(attributes, name)
+	if !ok || value.Type() != cty.Bool || value.IsNull() {
+		return fallback
+	}
+	return value.True()
+}
+
+func literalBlockAttributes(block *hcl.Block) (map[string]json.RawMessage, error) {
+	attributes, diags := block.Body.JustAttributes()
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	values := make(map[string]json.RawMessage, len(attributes))
+	for name := range attributes {
+		value, ok := literalAttribute(attributes, name)
+		if !ok {
+			values[name] = json.RawMessage("null")
+			continue
+		}
+
+		encoded, err := ctyjson.SimpleJSONValue{Value: value}.MarshalJSON()
+		if err != nil {
+			return nil, fmt.Errorf("`%s`: %w", name, err)
+		}
+		values[name] = encoded
+	}
+
+	return values, nil
+}
+
+// ctyToAttrValue converts a literal HCL value into the framework value the
+// provider would receive for a dynamic attribute.
+func ctyToAttrValue(value cty.Value) (attr.Value, error) {
+	ctx := context.Background()
+	ty := value.Type()
+
+	if value.IsNull() {
+		return types.DynamicNull(), nil
+	}
+
+	switch {
+	case ty == cty.String:
+		return types.StringValue(value.AsString()), nil
+	case ty == cty.Number:
+		return types.NumberValue(value.AsBigFloat()), nil
+	case ty == cty.Bool:
+		return types.BoolValue(value.True()), nil
+	case ty.IsTupleType() || ty.IsListType() || ty.IsSetType():
+		var elementTypes []attr.Type
+		var elements []attr.Value
+		for it := value.ElementIterator(); it.Next(); {
+			_, element := it.Element()
+			converted, err := ctyToAttrValue(element)
+			if err != nil {
+				return nil, err
+			}
+			elementTypes = append(elementTypes, converted.Type(ctx))
+			elements = append(elements, converted)
+		}
+		tuple, diags := types.TupleValue(elementTypes, elements)
+		if diags.HasError() {
+			return nil, diagnosticsError(diags)
+		}
+		return tuple, nil
+	case ty.IsObjectType() || ty.IsMapType():
+		attributeTypes := map[string]attr.Type{}
+		attributes := map[string]attr.Value{}
+		for it := value.ElementIterator(); it.Next(); {
+			key, element := it.Element()
+			converted, err := ctyToAttrValue(element)
+			if err != nil {
+				return nil, err
+			}
+			attributeTypes[key.AsString()] = converted.Type(ctx)
+			attributes[key.AsString()] = converted
+		}
+		object, diags := types.ObjectValue(attributeTypes, attributes)
+		if diags.HasError() {
+			return nil, diagnosticsError(diags)
+		}
+		return object, nil
+	default:
+		return nil, fmt.Errorf("unsupported value type %s", ty.FriendlyName())
+	}
+}
+
+func diagnosticsError(diags diag.Diagnostics) error {
+	messages := make([]string, 0, len(diags.Errors()))
+	for _, d := range diags.Errors() {
+		messages = append(messages, fmt.Sprintf("%s: %s", d.Summary(), d.Detail()))
+	}
+	return fmt.Errorf("%s", strings.Join(messages, "; "))
+}
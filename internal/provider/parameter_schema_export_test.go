@@ -0,0 +1,180 @@
+// Copyright (c) WANIX Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExportParameterSchemas(t *testing.T) {
+	dir := t.TempDir()
+	template := `
+variable "fallback_image" {
+  type = string
+}
+
+data "manidae_parameter" "instance_type" {
+  name         = "instance_type"
+  display_name = "Instance type"
+  default      = "SA2.MEDIUM2"
+  mutable      = false
+  aliases      = ["machine_type"]
+  order        = 1
+  group        = "Compute"
+
+  option {
+    name  = "Small"
+    value = "SA2.MEDIUM2"
+  }
+}
+
+data "manidae_parameter" "ports" {
+  name    = "ports"
+  default = [80, 443]
+
+  validation {
+    max_items = 4
+  }
+}
+
+data "manidae_parameter" "image" {
+  name    = "image"
+  type    = "string"
+  default = var.fallback_image
+
+  visible_when {
+    parameter = data.manidae_parameter.instance_type.name
+    values    = ["SA2.MEDIUM2"]
+  }
+}
+
+data "manidae_instance" "this" {}
+`
+	if err := os.WriteFile(filepath.Join(dir, "main.tf"), []byte(template), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ExportParameterSchemas(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("expected 3 parameters, got %d", len(got))
+	}
+
+	instanceType := got[0]
+	if instanceType.Name != "instance_type" || instanceType.Type != parameterTypeString || instanceType.Mutable {
+		t.Fatalf("unexpected instance_type export: %+v", instanceType)
+	}
+	if instanceType.EnvironmentVariable != ParameterEnvironmentVariable("instance_type") {
+		t.Fatalf("unexpected environment variable %q", instanceType.EnvironmentVariable)
+	}
+	if instanceType.Order == nil || *instanceType.Order != 1 || instanceType.Group == nil || *instanceType.Group != "Compute" {
+		t.Fatalf("unexpected UI metadata: %+v", instanceType)
+	}
+	if instanceType.FormType == nil || *instanceType.FormType != parameterFormDropdown {
+		t.Fatalf("expected default form type %q, got %v", parameterFormDropdown, instanceType.FormType)
+	}
+	if len(instanceType.Aliases) != 1 || instanceType.Aliases[0] != "machine_type" {
+		t.Fatalf("unexpected aliases: %v", instanceType.Aliases)
+	}
+	if len(instanceType.Options) != 1 || string(instanceType.Options[0]["value"]) != `"SA2.MEDIUM2"` {
+		t.Fatalf("unexpected options: %v", instanceType.Options)
+	}
+
+	ports := got[1]
+	if ports.Type != parameterTypeListNumber || string(ports.Default) != "[80,443]" || string(ports.Validation["max_items"]) != "4" {
+		t.Fatalf("unexpected ports export: %+v", ports)
+	}
+
+	image := got[2]
+	if image.Type != parameterTypeString || image.Default != nil {
+		t.Fatalf("expected non-literal default to be exported as null, got %+v", image)
+	}
+	if len(image.VisibleWhen) != 1 || string(image.VisibleWhen[0]["parameter"]) != `"instance_type"` || string(image.VisibleWhen[0]["values"]) != `["SA2.MEDIUM2"]` {
+		t.Fatalf("unexpected visible_when: %v", image.VisibleWhen)
+	}
+}
+
+func TestExportParameterSchemas_RejectsUnknownArguments(t *testing.T) {
+	dir := t.TempDir()
+	template := `
+data "manidae_parameter" "typo" {
+  name    = "typo"
+  defualt = 1
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "main.tf"), []byte(template), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ExportParameterSchemas(dir); err == nil {
+		t.Fatalf("expected error, got none")
+	}
+}
+
+func TestExportParameterSchemas_SkipsSubdirectories(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "main.tf"), []byte("data \"manidae_parameter\" \"region\" {\n  name    = \"region\"\n  default = \"eu\"\n}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	nested := filepath.Join(dir, "modules", "network")
+	if err := os.MkdirAll(nested, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(nested, "main.tf"), []byte("data \"manidae_parameter\" \"cidr\" {\n  name    = \"cidr\"\n  default = \"10.0.0.0/16\"\n}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ExportParameterSchemas(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(got) != 1 || got[0].Name != "region" {
+		t.Fatalf("expected only the root module's parameter, got %+v", got)
+	}
+}
+
+func TestExportParameterSchemas_OmitsSensitiveDefault(t *testing.T) {
+	dir := t.TempDir()
+	template := `
+data "manidae_sensitive_parameter" "api_key" {
+  name    = "api_key"
+  default = "s3cret"
+}
+
+data "manidae_parameter" "db_password" {
+  name      = "db_password"
+  default   = "hunter2"
+  sensitive = var.secret
+}
+
+data "manidae_parameter" "region" {
+  name      = "region"
+  default   = "eu-west-1"
+  sensitive = false
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "main.tf"), []byte(template), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ExportParameterSchemas(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("expected 3 parameters, got %+v", got)
+	}
+	for _, parameter := range got[:2] {
+		if parameter.Type != parameterTypeString || !parameter.Sensitive || parameter.Default != nil {
+			t.Fatalf("expected the sensitive default of %q to be omitted, got %+v", parameter.Name, parameter)
+		}
+	}
+	if got[2].Sensitive || string(got[2].Default) != `"eu-west-1"` {
+		t.Fatalf("expected the default of %q to be exported, got %+v", got[2].Name, got[2])
+	}
+}
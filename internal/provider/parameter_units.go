@@ -0,0 +1,197 @@
+// Copyright (c) WANIX Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// byteUnitSizes maps the units accepted for `type = "bytes"` to their size in
+// bytes. As in Kubernetes quantities, SI units are powers of 1000 and IEC
+// units powers of 1024, so "50G" is 50 GB and "50Gi" is 50 GiB.
+var byteUnitSizes = map[string]int64{
+	"B":   1,
+	"KB":  1_000,
+	"MB":  1_000_000,
+	"GB":  1_000_000_000,
+	"TB":  1_000_000_000_000,
+	"PB":  1_000_000_000_000_000,
+	"KiB": 1 << 10,
+	"MiB": 1 << 20,
+	"GiB": 1 << 30,
+	"TiB": 1 << 40,
+	"PiB": 1 << 50,
+}
+
+// durationUnitSizes maps the units accepted for `type = "duration"` to their
+// size in milliseconds.
+var durationUnitSizes = map[string]int64{
+	"ms": 1,
+	"s":  1_000,
+	"m":  60_000,
+	"h":  3_600_000,
+	"d":  86_400_000,
+}
+
+// parameterDefaultUnits is the unit a bytes or duration value is expressed in
+// when `unit` is not set.
+var parameterDefaultUnits = map[string]string{
+	parameterTypeBytes:    "B",
+	parameterTypeDuration: "s",
+}
+
+var (
+	plainNumberPattern     = regexp.MustCompile(`^[+-]?(\d+(\.\d*)?|\.\d+)([eE][+-]?\d+)?$`)
+	byteQuantityPattern    = regexp.MustCompile(`^(\d+(?:\.\d*)?|\.\d+)\s*([A-Za-z]+)$`)
+	durationPartPattern    = regexp.MustCompile(`^(\d+(?:\.\d*)?|\.\d+)(ms|s|m|h|d)`)
+	byteUnitsBySuffix      = map[string]string{}
+	supportedByteUnits     = sortedUnits(byteUnitSizes)
+	supportedDurationUnits = sortedUnits(durationUnitSizes)
+)
+
+func init() {
+	// Byte suffixes are case-insensitive and the trailing "B" is optional,
+	// so "g", "GB" and "gb" all mean gigabytes.
+	for unit := range byteUnitSizes {
+		suffix := strings.ToLower(unit)
+		byteUnitsBySuffix[suffix] = unit
+		if len(suffix) > 1 {
+			byteUnitsBySuffix[strings.TrimSuffix(suffix, "b")] = unit
+		}
+	}
+}
+
+// resolveParameterUnit returns the canonical unit for a bytes or duration
+// parameter, defaulting when `unit` is unset. Other types do not accept
+// `unit` and resolve to "".
+func resolveParameterUnit(parameterType string, unitAttr types.String) (string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	defaultUnit, hasUnit := parameterDefaultUnits[parameterType]
+	if !hasUnit {
+		if !unitAttr.IsNull() {
+			diags.AddAttributeError(path.Root("unit"), "Invalid unit", fmt.Sprintf("`unit` is only supported when `type` is %q or %q", parameterTypeBytes, parameterTypeDuration))
+		}
+		return "", diags
+	}
+
+	if unitAttr.IsUnknown() {
+		diags.AddAttributeError(path.Root("unit"), "Invalid unit", "`unit` must be known")
+		return "", diags
+	}
+	if unitAttr.IsNull() {
+		return defaultUnit, diags
+	}
+
+	unit, ok := canonicalParameterUnit(parameterType, strings.TrimSpace(unitAttr.ValueString()))
+	if !ok {
+		diags.AddAttributeError(path.Root("unit"), "Invalid unit", fmt.Sprintf("unsupported `unit` %q for `type = %q` (supported: %s)", unitAttr.ValueString(), parameterType, quoteJoin(parameterUnits(parameterType))))
+		return "", diags
+	}
+	return unit, diags
+}
+
+// parseParameterNumber parses a number, or for bytes and duration parameters
+// a quantity such as "50GiB" or "1h30m". Bare numbers are taken to be in
+// unit, and the result is expressed in unit.
+func parseParameterNumber(parameterType string, unit string, raw string) (*big.Rat, error) {
+	trimmed := strings.TrimSpace(raw)
+
+	if plainNumberPattern.MatchString(trimmed) {
+		number, ok := new(big.Float).SetString(trimmed)
+		if !ok {
+			return nil, fmt.Errorf("not a number")
+		}
+		// Quantities with a unit cannot carry a sign, so bare numbers
+		// must not either.
+		if number.Sign() < 0 && (parameterType == parameterTypeBytes || parameterType == parameterTypeDuration) {
+			return nil, fmt.Errorf("must not be negative")
+		}
+		return parameterNumber(number), nil
+	}
+
+	switch parameterType {
+	case parameterTypeBytes:
+		match := byteQuantityPattern.FindStringSubmatch(trimmed)
+		if match == nil {
+			return nil, fmt.Errorf("expected a number with an optional unit, e.g. \"50GiB\" or \"512M\"")
+		}
+		suffixUnit, ok := canonicalParameterUnit(parameterType, match[2])
+		if !ok {
+			return nil, fmt.Errorf("unknown unit %q (supported: %s)", match[2], quoteJoin(supportedByteUnits))
+		}
+		amount, _ := new(big.Rat).SetString(match[1])
+		return convertParameterUnit(parameterType, amount, suffixUnit, unit), nil
+	case parameterTypeDuration:
+		if trimmed == "" {
+			return nil, fmt.Errorf("expected a duration, e.g. \"30m\" or \"1h30m\"")
+		}
+		total := new(big.Rat)
+		for rest := trimmed; rest != ""; {
+			match := durationPartPattern.FindStringSubmatch(rest)
+			if match == nil {
+				return nil, fmt.Errorf("expected a duration such as \"30m\" or \"1h30m\" (supported units: %s)", quoteJoin(supportedDurationUnits))
+			}
+			amount, _ := new(big.Rat).SetString(match[1])
+			total.Add(total, convertParameterUnit(parameterType, amount, match[2], unit))
+			rest = rest[len(match[0]):]
+		}
+		return total, nil
+	default:
+		return nil, fmt.Errorf("not a number")
+	}
+}
+
+// convertParameterUnit converts amount from one unit to another.
+func convertParameterUnit(parameterType string, amount *big.Rat, from string, to string) *big.Rat {
+	sizes := byteUnitSizes
+	if parameterType == parameterTypeDuration {
+		sizes = durationUnitSizes
+	}
+	factor := big.NewRat(sizes[from], sizes[to])
+	return new(big.Rat).Mul(amount, factor)
+}
+
+func canonicalParameterUnit(parameterType string, unit string) (string, bool) {
+	switch parameterType {
+	case parameterTypeBytes:
+		canonical, ok := byteUnitsBySuffix[strings.ToLower(unit)]
+		return canonical, ok
+	case parameterTypeDuration:
+		_, ok := durationUnitSizes[unit]
+		return unit, ok
+	default:
+		return "", false
+	}
+}
+
+func parameterUnits(parameterType string) []string {
+	if parameterType == parameterTypeDuration {
+		return supportedDurationUnits
+	}
+	return supportedByteUnits
+}
+
+// sortedUnits lists units from smallest to largest.
+func sortedUnits(sizes map[string]int64) []string {
+	units := make([]string, 0, len(sizes))
+	for unit := range sizes {
+		units = append(units, unit)
+	}
+	sort.Slice(units, func(i, j int) bool {
+		if sizes[units[i]] != sizes[units[j]] {
+			return sizes[units[i]] < sizes[units[j]]
+		}
+		return units[i] < units[j]
+	})
+	return units
+}
@@ -0,0 +1,832 @@
+// Copyright (c) WANIX Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// parameterTypeValidationAttributes lists the `validation` attributes accepted
+// by each parameter type.
+var parameterTypeValidationAttributes = map[string][]string{
+	parameterTypeString:     {"regex", "min_length", "max_length", "error"},
+	parameterTypeNumber:     {"min", "max", "step", "integer", "monotonic"},
+	parameterTypeBool:       nil,
+	parameterTypeBytes:      {"min", "max", "step", "integer", "monotonic"},
+	parameterTypeDuration:   {"min", "max", "step", "integer", "monotonic"},
+	parameterTypeListString: {"min_items", "max_items", "regex", "min_length", "max_length", "error"},
+	parameterTypeListNumber: {"min", "max", "step", "integer", "min_items", "max_items"},
+	parameterTypeJSON:       {"json_schema"},
+	parameterTypeMapString:  {"json_schema"},
+}
+
+// validateParameterDefinition checks the parts of a parameter definition that
+// do not depend on the resolved value, so they can be reported by
+// `terraform validate`. Each diagnostic is attached to the offending
+// attribute; unknown attributes are skipped.
+func validateParameterDefinition(parameterType string, unit string, validation *parameterValidationModel, options []parameterOptionModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	allowedValidation, ok := parameterTypeValidationAttributes[parameterType]
+	if !ok {
+		diags.AddAttributeError(path.Root("type"), "Invalid type", fmt.Sprintf("unsupported `type` %q", parameterType))
+		return diags
+	}
+
+	if validation != nil {
+		validationPath := path.Root("validation")
+
+		for _, attribute := range validation.setAttributes() {
+			if !slices.Contains(allowedValidation, attribute) {
+				diags.AddAttributeError(validationPath.AtName(attribute), "Invalid validation", fmt.Sprintf("`validation.%s` is not supported when `type = %q`", attribute, parameterType))
+			}
+		}
+		if diags.HasError() {
+			return diags
+		}
+
+		limits, limitDiags := resolveParameterNumberLimits(parameterType, unit, validation)
+		diags.Append(limitDiags...)
+
+		if limits.Min != nil && limits.Max != nil && limits.Min.Cmp(limits.Max) > 0 {
+			diags.AddAttributeError(validationPath.AtName("min"), "Invalid validation", "`validation.min` must be <= `validation.max`")
+		}
+
+		if limits.Step != nil && limits.Step.Sign() <= 0 {
+			diags.AddAttributeError(validationPath.AtName("step"), "Invalid validation", "`validation.step` must be > 0")
+		}
+
+		if isKnownValue(validation.MinItems) && validation.MinItems.ValueInt64() < 0 {
+			diags.AddAttributeError(validationPath.AtName("min_items"), "Invalid validation", "`validation.min_items` must be >= 0")
+		} else if isKnownValue(validation.MinItems) && isKnownValue(validation.MaxItems) && validation.MinItems.ValueInt64() > validation.MaxItems.ValueInt64() {
+			diags.AddAttributeError(validationPath.AtName("min_items"), "Invalid validation", "`validation.min_items` must be <= `validation.max_items`")
+		}
+
+		if isKnownValue(validation.MinLength) && validation.MinLength.ValueInt64() < 0 {
+			diags.AddAttributeError(validationPath.AtName("min_length"), "Invalid validation", "`validation.min_length` must be >= 0")
+		} else if isKnownValue(validation.MinLength) && isKnownValue(validation.MaxLength) && validation.MinLength.ValueInt64() > validation.MaxLength.ValueInt64() {
+			diags.AddAttributeError(validationPath.AtName("min_length"), "Invalid validation", "`validation.min_length` must be <= `validation.max_length`")
+		}
+
+		if isKnownValue(validation.Regex) {
+			if _, err := regexp.Compile(validation.Regex.ValueString()); err != nil {
+				diags.AddAttributeError(validationPath.AtName("regex"), "Invalid validation", fmt.Sprintf("`validation.regex` is not a valid regular expression: %s", err))
+			}
+		}
+
+		if isKnownValue(validation.JSONSchema) {
+			if _, err := compileParameterJSONSchema(validation.JSONSchema.ValueString()); err != nil {
+				diags.AddAttributeError(validationPath.AtName("json_schema"), "Invalid validation", fmt.Sprintf("`validation.json_schema` is not a valid JSON Schema: %s", err))
+			}
+		}
+
+		if isKnownValue(validation.Monotonic) {
+			direction := validation.Monotonic.ValueString()
+			if direction != parameterMonotonicIncreasing && direction != parameterMonotonicDecreasing {
+				diags.AddAttributeError(validationPath.AtName("monotonic"), "Invalid validation", fmt.Sprintf("unsupported `validation.monotonic` %q (supported: %q, %q)", direction, parameterMonotonicIncreasing, parameterMonotonicDecreasing))
+			}
+		}
+	}
+
+	if len(options) > 0 {
+		switch parameterType {
+		case parameterTypeBool:
+			diags.AddAttributeError(path.Root("option"), "Invalid option", "`option` blocks are not supported when `type = \"bool\"` (the value is always true or false)")
+			return diags
+		case parameterTypeJSON, parameterTypeMapString:
+			diags.AddAttributeError(path.Root("option"), "Invalid option", fmt.Sprintf("`option` blocks are not supported when `type = %q`; use `validation.json_schema` to restrict the value", parameterType))
+			return diags
+		}
+	}
+
+	for i, opt := range options {
+		valuePath := path.Root("option").AtListIndex(i).AtName("value")
+		if opt.Value.IsNull() {
+			diags.AddAttributeError(valuePath, "Invalid option", fmt.Sprintf("option[%d].value must be set", i))
+			continue
+		}
+		if isNumericParameterType(parameterType) && isKnownValue(opt.Value) {
+			if _, err := parseParameterNumber(parameterType, unit, opt.Value.ValueString()); err != nil {
+				diags.AddAttributeError(valuePath, "Invalid option", fmt.Sprintf("option[%d].value %q cannot be parsed as %s: %s", i, opt.Value.ValueString(), parameterType, err))
+			}
+		}
+	}
+
+	return diags
+}
+
+// parameterDefinitionKnown reports whether every configured validation and
+// option value is known, which is required before a value can be checked
+// against them.
+func parameterDefinitionKnown(validation *parameterValidationModel, options []parameterOptionModel) bool {
+	if validation != nil {
+		for _, value := range validation.values() {
+			if !isWhollyKnown(value) {
+				return false
+			}
+		}
+	}
+	for _, opt := range options {
+		if opt.Value.IsUnknown() {
+			return false
+		}
+	}
+	return true
+}
+
+func validateParameterMonotonic(p parameterRef, value attr.Value, previous attr.Value, validation *parameterValidationModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if validation == nil || validation.Monotonic.IsNull() {
+		return diags
+	}
+	if validation.Monotonic.IsUnknown() {
+		diags.AddAttributeError(path.Root("validation").AtName("monotonic"), "Invalid validation", "`validation.monotonic` must be known when set")
+		return diags
+	}
+
+	direction := validation.Monotonic.ValueString()
+	if direction != parameterMonotonicIncreasing && direction != parameterMonotonicDecreasing {
+		diags.AddAttributeError(path.Root("validation").AtName("monotonic"), "Invalid validation", fmt.Sprintf("unsupported `validation.monotonic` %q (supported: %q, %q)", direction, parameterMonotonicIncreasing, parameterMonotonicDecreasing))
+		return diags
+	}
+
+	if previous == nil {
+		return diags
+	}
+
+	currentNumber, ok := value.(types.Number)
+	if !ok {
+		diags.AddError("Invalid value", "expected resolved value to be a number")
+		return diags
+	}
+	previousNumber, ok := previous.(types.Number)
+	if !ok {
+		diags.AddError("Invalid previous value", "expected previous value to be a number")
+		return diags
+	}
+
+	current, last := currentNumber.ValueBigFloat(), previousNumber.ValueBigFloat()
+	switch cmp := current.Cmp(last); {
+	case direction == parameterMonotonicIncreasing && cmp < 0:
+		diags.Append(parameterValueError(p, fmt.Sprintf("value %s is less than the previous value %s, but validation.monotonic is %q", p.showNumber(parameterNumber(current)), p.showNumber(parameterNumber(last)), direction)))
+	case direction == parameterMonotonicDecreasing && cmp > 0:
+		diags.Append(parameterValueError(p, fmt.Sprintf("value %s is greater than the previous value %s, but validation.monotonic is %q", p.showNumber(parameterNumber(current)), p.showNumber(parameterNumber(last)), direction)))
+	}
+
+	return diags
+}
+
+// sensitiveParameterError rejects `sensitive = true` on `manidae_parameter`,
+// whose `value` is not marked sensitive.
+func sensitiveParameterError() diag.Diagnostic {
+	return diag.NewAttributeErrorDiagnostic(path.Root("sensitive"), "Unsupported sensitive parameter", "`manidae_parameter` does not mark `value` sensitive, so `sensitive = true` is not supported; declare the parameter with `data \"manidae_sensitive_parameter\"` instead")
+}
+
+func validateEphemeralParameter(defaultValue types.Dynamic, mutable types.Bool, validation *parameterValidationModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if defaultValue.IsNull() {
+		diags.AddAttributeError(path.Root("default"), "Missing default", "ephemeral parameters require `default`, which applies to every build that does not supply a value")
+	}
+	if !mutable.IsUnknown() && !mutable.IsNull() && !mutable.ValueBool() {
+		diags.AddAttributeError(path.Root("mutable"), "Invalid ephemeral parameter", "`mutable = false` is not supported for ephemeral parameters")
+	}
+	if validation != nil && !validation.Monotonic.IsNull() {
+		diags.AddAttributeError(path.Root("validation").AtName("monotonic"), "Invalid ephemeral parameter", "`validation.monotonic` is not supported for ephemeral parameters")
+	}
+
+	return diags
+}
+
+// validateParameterAliases rejects empty and repeated aliases, and aliases
+// equal to the parameter's own name. Unknown names are skipped.
+func validateParameterAliases(name types.String, aliases types.List) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if aliases.IsNull() || aliases.IsUnknown() {
+		return diags
+	}
+
+	seen := make(map[string]bool)
+	for i, element := range aliases.Elements() {
+		alias, ok := element.(types.String)
+		if !ok || alias.IsUnknown() {
+			continue
+		}
+		aliasPath := path.Root("aliases").AtListIndex(i)
+
+		switch value := alias.ValueString(); {
+		case alias.IsNull() || strings.TrimSpace(value) == "":
+			diags.AddAttributeError(aliasPath, "Invalid alias", "aliases must not be empty")
+		case !name.IsUnknown() && value == name.ValueString():
+			diags.AddAttributeError(aliasPath, "Invalid alias", fmt.Sprintf("alias %q is the parameter's own name", value))
+		case seen[value]:
+			diags.AddAttributeError(aliasPath, "Invalid alias", fmt.Sprintf("alias %q is listed more than once", value))
+		default:
+			seen[value] = true
+		}
+	}
+
+	return diags
+}
+
+// validateParameterImmutable rejects a changed value for an immutable
+// parameter. The check only applies once the instance exists, and is skipped
+// when the platform supplied no action or previous value.
+func validateParameterImmutable(p parameterRef, action string, value attr.Value, previous attr.Value) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if action == "" || action == instanceActionCreate || previous == nil {
+		return diags
+	}
+
+	if !value.Equal(previous) {
+		diags.Append(parameterValueError(p, fmt.Sprintf("value %s differs from the previous value %s, but the parameter is immutable (`mutable = false`) and cannot change after the instance is created (action %q)", p.show(value.String()), p.show(previous.String()), action)))
+	}
+
+	return diags
+}
+
+func validateParameterValue(p parameterRef, parameterType string, value attr.Value, validation *parameterValidationModel, options []parameterOptionModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	diags.Append(validateParameterDefinition(parameterType, p.Unit, validation, options)...)
+	if diags.HasError() {
+		return diags
+	}
+	if !parameterDefinitionKnown(validation, options) {
+		diags.AddError("Invalid validation", "`validation` and `option` values must be known")
+		return diags
+	}
+
+	limits, _ := resolveParameterNumberLimits(parameterType, p.Unit, validation)
+
+	switch parameterType {
+	case parameterTypeString:
+		stringValue, ok := value.(types.String)
+		if !ok {
+			diags.AddError("Invalid value", "expected resolved value to be a string")
+			return diags
+		}
+		if stringValue.IsNull() || stringValue.IsUnknown() {
+			diags.AddError("Invalid value", "resolved value must be known")
+			return diags
+		}
+
+		diags.Append(validateStringRules(p, p.label(), stringValue.ValueString(), validation)...)
+		if diags.HasError() {
+			return diags
+		}
+
+		if len(options) == 0 {
+			return diags
+		}
+
+		if !slices.Contains(parameterOptionValues(options), stringValue.ValueString()) {
+			diags.Append(parameterValueError(p, fmt.Sprintf("%s %s is not one of the configured options", p.label(), p.quote(stringValue.ValueString()))))
+		}
+
+		return diags
+	case parameterTypeNumber, parameterTypeBytes, parameterTypeDuration:
+		numberValue, ok := value.(types.Number)
+		if !ok {
+			diags.AddError("Invalid value", "expected resolved value to be a number")
+			return diags
+		}
+		if numberValue.IsNull() || numberValue.IsUnknown() {
+			diags.AddError("Invalid value", "resolved value must be known")
+			return diags
+		}
+
+		diags.Append(validateNumberBounds(p, p.label(), numberValue.ValueBigFloat(), limits)...)
+		if diags.HasError() {
+			return diags
+		}
+
+		diags.Append(validateNumberRules(p, p.label(), numberValue.ValueBigFloat(), limits)...)
+		if diags.HasError() {
+			return diags
+		}
+
+		if len(options) == 0 {
+			return diags
+		}
+
+		diags.Append(validateNumberOption(p, parameterType, p.label(), numberValue.ValueBigFloat(), options)...)
+		return diags
+	case parameterTypeBool:
+		boolValue, ok := value.(types.Bool)
+		if !ok {
+			diags.AddError("Invalid value", "expected resolved value to be a bool")
+			return diags
+		}
+		if boolValue.IsNull() || boolValue.IsUnknown() {
+			diags.AddError("Invalid value", "resolved value must be known")
+			return diags
+		}
+
+		return diags
+	case parameterTypeListString, parameterTypeListNumber:
+		listValue, ok := value.(types.List)
+		if !ok {
+			diags.AddError("Invalid value", "expected resolved value to be a list")
+			return diags
+		}
+		if listValue.IsNull() || listValue.IsUnknown() {
+			diags.AddError("Invalid value", "resolved value must be known")
+			return diags
+		}
+
+		elements := listValue.Elements()
+		diags.Append(validateListItemCount(p, len(elements), validation)...)
+		if diags.HasError() {
+			return diags
+		}
+
+		if parameterType == parameterTypeListString {
+			for i, element := range elements {
+				stringValue, ok := element.(types.String)
+				if !ok {
+					diags.AddError("Invalid value", fmt.Sprintf("expected %s[%d] to be a string", p.label(), i))
+					return diags
+				}
+				diags.Append(validateStringRules(p, fmt.Sprintf("%s[%d]", p.label(), i), stringValue.ValueString(), validation)...)
+				if diags.HasError() {
+					return diags
+				}
+			}
+		}
+
+		if parameterType == parameterTypeListNumber {
+			for i, element := range elements {
+				numberValue, ok := element.(types.Number)
+				if !ok {
+					diags.AddError("Invalid value", fmt.Sprintf("expected %s[%d] to be a number", p.label(), i))
+					return diags
+				}
+				label := fmt.Sprintf("%s[%d]", p.label(), i)
+				diags.Append(validateNumberBounds(p, label, numberValue.ValueBigFloat(), limits)...)
+				if diags.HasError() {
+					return diags
+				}
+				diags.Append(validateNumberRules(p, label, numberValue.ValueBigFloat(), limits)...)
+				if diags.HasError() {
+					return diags
+				}
+			}
+		}
+
+		if len(options) == 0 {
+			return diags
+		}
+
+		diags.Append(validateListOptions(p, parameterType, elements, options)...)
+		return diags
+	case parameterTypeJSON, parameterTypeMapString:
+		if !isWhollyKnown(value) {
+			diags.AddError("Invalid value", "resolved value must be known")
+			return diags
+		}
+		if _, ok := value.(types.Map); parameterType == parameterTypeMapString && !ok {
+			diags.AddError("Invalid value", "expected resolved value to be a map")
+			return diags
+		}
+
+		diags.Append(validateParameterJSONSchema(p, value, validation)...)
+		return diags
+	default:
+		diags.AddError("Invalid type", fmt.Sprintf("unsupported `type` %q", parameterType))
+		return diags
+	}
+}
+
+// validateNumberBounds checks val against `validation.min` and
+// `validation.max`.
+func validateNumberBounds(p parameterRef, label string, val *big.Float, limits parameterNumberLimits) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	value := parameterNumber(val)
+
+	if limits.Min != nil && value.Cmp(limits.Min) < 0 {
+		diags.Append(parameterValueError(p, fmt.Sprintf("%s %s is less than validation.min %s", label, p.showNumber(value), p.number(limits.Min))))
+		return diags
+	}
+
+	if limits.Max != nil && value.Cmp(limits.Max) > 0 {
+		diags.Append(parameterValueError(p, fmt.Sprintf("%s %s is greater than validation.max %s", label, p.showNumber(value), p.number(limits.Max))))
+		return diags
+	}
+
+	return diags
+}
+
+// validateStringRules checks val against the string rules in validation, which
+// validateParameterDefinition has already checked.
+func validateStringRules(p parameterRef, label string, val string, validation *parameterValidationModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if validation == nil {
+		return diags
+	}
+
+	var re *regexp.Regexp
+	if !validation.Regex.IsNull() {
+		re = regexp.MustCompile(validation.Regex.ValueString())
+	}
+
+	// The length of a secret narrows it down, so it is only shown for
+	// parameters that are not sensitive.
+	length := int64(utf8.RuneCountInString(val))
+	lengthDetail := fmt.Sprintf("%d characters long, ", length)
+	if p.Sensitive {
+		lengthDetail = ""
+	}
+	var rule, detail string
+	switch {
+	case !validation.MinLength.IsNull() && length < validation.MinLength.ValueInt64():
+		rule = fmt.Sprintf("min_length = %d", validation.MinLength.ValueInt64())
+		detail = fmt.Sprintf("%s %s is %sshorter than validation.min_length %d", label, p.quote(val), lengthDetail, validation.MinLength.ValueInt64())
+	case !validation.MaxLength.IsNull() && length > validation.MaxLength.ValueInt64():
+		rule = fmt.Sprintf("max_length = %d", validation.MaxLength.ValueInt64())
+		detail = fmt.Sprintf("%s %s is %slonger than validation.max_length %d", label, p.quote(val), lengthDetail, validation.MaxLength.ValueInt64())
+	case re != nil && !re.MatchString(val):
+		rule = fmt.Sprintf("regex = %q", re.String())
+		detail = fmt.Sprintf("%s %s does not match validation.regex %q", label, p.quote(val), re.String())
+	default:
+		return diags
+	}
+
+	if !validation.Error.IsNull() {
+		detail = strings.NewReplacer("{name}", p.Name, "{value}", p.show(val), "{rule}", rule).Replace(validation.Error.ValueString())
+	}
+
+	diags.Append(parameterValueError(p, detail))
+	return diags
+}
+
+func validateListItemCount(p parameterRef, count int, validation *parameterValidationModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if validation == nil {
+		return diags
+	}
+
+	countDetail := fmt.Sprintf("%d items, ", count)
+	if p.Sensitive {
+		countDetail = ""
+	}
+
+	if !validation.MinItems.IsNull() && int64(count) < validation.MinItems.ValueInt64() {
+		diags.Append(parameterValueError(p, fmt.Sprintf("%s has %sfewer than validation.min_items %d", p.label(), countDetail, validation.MinItems.ValueInt64())))
+		return diags
+	}
+
+	if !validation.MaxItems.IsNull() && int64(count) > validation.MaxItems.ValueInt64() {
+		diags.Append(parameterValueError(p, fmt.Sprintf("%s has %smore than validation.max_items %d", p.label(), countDetail, validation.MaxItems.ValueInt64())))
+		return diags
+	}
+
+	return diags
+}
+
+func validateListOptions(p parameterRef, parameterType string, elements []attr.Value, options []parameterOptionModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	allowed := parameterOptionValues(options)
+
+	if parameterType == parameterTypeListString {
+		for i, element := range elements {
+			stringValue, ok := element.(types.String)
+			if !ok {
+				diags.AddError("Invalid value", fmt.Sprintf("expected %s[%d] to be a string", p.label(), i))
+				return diags
+			}
+			if !slices.Contains(allowed, stringValue.ValueString()) {
+				diags.Append(parameterValueError(p, fmt.Sprintf("%s[%d] %s is not one of the configured options", p.label(), i, p.quote(stringValue.ValueString()))))
+			}
+		}
+		return diags
+	}
+
+	for i, element := range elements {
+		numberValue, ok := element.(types.Number)
+		if !ok {
+			diags.AddError("Invalid value", fmt.Sprintf("expected %s[%d] to be a number", p.label(), i))
+			return diags
+		}
+		diags.Append(validateNumberOption(p, parameterType, fmt.Sprintf("%s[%d]", p.label(), i), numberValue.ValueBigFloat(), options)...)
+	}
+
+	return diags
+}
+
+// validateNumberRules checks val against `validation.integer` and
+// `validation.step`. Steps are counted from `validation.min`, or from zero
+// when it is unset.
+func validateNumberRules(p parameterRef, label string, val *big.Float, limits parameterNumberLimits) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	value := parameterNumber(val)
+
+	if limits.Integer && !value.IsInt() {
+		lower := new(big.Rat).SetInt(ratFloor(value))
+		upper := new(big.Rat).Add(lower, big.NewRat(1, 1))
+		diags.Append(parameterValueError(p, fmt.Sprintf("%s %s is not a whole number%s", label, p.showNumber(value), nearestAllowedValues(p, limits, lower, upper))))
+		return diags
+	}
+
+	if limits.Step == nil {
+		return diags
+	}
+
+	base := new(big.Rat)
+	if limits.Min != nil {
+		base = limits.Min
+	}
+
+	steps := new(big.Rat).Quo(new(big.Rat).Sub(value, base), limits.Step)
+	if steps.IsInt() {
+		return diags
+	}
+
+	lower := new(big.Rat).Add(base, new(big.Rat).Mul(new(big.Rat).SetInt(ratFloor(steps)), limits.Step))
+	upper := new(big.Rat).Add(lower, limits.Step)
+	diags.Append(parameterValueError(p, fmt.Sprintf("%s %s is not a multiple of validation.step %s counted from %s%s", label, p.showNumber(value), p.number(limits.Step), p.number(base), nearestAllowedValues(p, limits, lower, upper))))
+	return diags
+}
+
+// validateNumberOption checks that val equals one of the numeric options,
+// naming the closest options on either side when it does not.
+func validateNumberOption(p parameterRef, parameterType string, label string, val *big.Float, options []parameterOptionModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	value := parameterNumber(val)
+
+	var lower, upper *big.Rat
+	for _, raw := range parameterOptionValues(options) {
+		option, err := parseParameterNumber(parameterType, p.Unit, raw)
+		if err != nil {
+			continue
+		}
+		switch cmp := option.Cmp(value); {
+		case cmp == 0:
+			return diags
+		case cmp < 0 && (lower == nil || option.Cmp(lower) > 0):
+			lower = option
+		case cmp > 0 && (upper == nil || option.Cmp(upper) < 0):
+			upper = option
+		}
+	}
+
+	diags.Append(parameterValueError(p, fmt.Sprintf("%s %s is not one of the configured options%s", label, p.showNumber(value), nearestAllowedValues(p, parameterNumberLimits{}, lower, upper))))
+	return diags
+}
+
+// nearestAllowedValues describes the allowed values on either side of a
+// rejected value for a diagnostic. Candidates that are nil or fall outside
+// validation.min/max are left out, and nothing is shown for sensitive
+// parameters since the neighbours would reveal the value.
+func nearestAllowedValues(p parameterRef, limits parameterNumberLimits, candidates ...*big.Rat) string {
+	if p.Sensitive {
+		return ""
+	}
+
+	var allowed []string
+	for _, candidate := range candidates {
+		if candidate == nil {
+			continue
+		}
+		if limits.Min != nil && candidate.Cmp(limits.Min) < 0 {
+			continue
+		}
+		if limits.Max != nil && candidate.Cmp(limits.Max) > 0 {
+			continue
+		}
+		allowed = append(allowed, p.number(candidate))
+	}
+
+	switch len(allowed) {
+	case 0:
+		return ""
+	case 1:
+		return fmt.Sprintf("; the nearest allowed value is %s", allowed[0])
+	default:
+		return fmt.Sprintf("; the nearest allowed values are %s", strings.Join(allowed, " and "))
+	}
+}
+
+// parameterNumberLimits holds the numeric validation attributes, expressed
+// in the parameter's unit.
+type parameterNumberLimits struct {
+	Min     *big.Rat
+	Max     *big.Rat
+	Step    *big.Rat
+	Integer bool
+}
+
+// resolveParameterNumberLimits reads `validation.min`, `validation.max` and
+// `validation.step`. They are numbers, or for bytes and duration parameters
+// may also be strings with units. Unset and unknown attributes are left nil.
+func resolveParameterNumberLimits(parameterType string, unit string, validation *parameterValidationModel) (parameterNumberLimits, diag.Diagnostics) {
+	var limits parameterNumberLimits
+	var diags diag.Diagnostics
+
+	if validation == nil {
+		return limits, diags
+	}
+
+	limits.Integer = validation.Integer.ValueBool()
+
+	for _, bound := range []struct {
+		name   string
+		value  types.Dynamic
+		target **big.Rat
+	}{
+		{"min", validation.Min, &limits.Min},
+		{"max", validation.Max, &limits.Max},
+		{"step", validation.Step, &limits.Step},
+	} {
+		if !isKnownValue(bound.value) {
+			continue
+		}
+
+		var number *big.Rat
+		var err error
+		switch v := bound.value.UnderlyingValue().(type) {
+		case types.Number:
+			number = parameterNumber(v.ValueBigFloat())
+		case types.String:
+			number, err = parseParameterNumber(parameterType, unit, v.ValueString())
+		default:
+			err = fmt.Errorf("expected a number")
+		}
+		if err != nil {
+			diags.AddAttributeError(path.Root("validation").AtName(bound.name), "Invalid validation", fmt.Sprintf("`validation.%s` cannot be parsed as %s: %s", bound.name, parameterType, err))
+			continue
+		}
+		*bound.target = number
+	}
+
+	return limits, diags
+}
+
+func isNumericParameterType(parameterType string) bool {
+	switch parameterType {
+	case parameterTypeNumber, parameterTypeBytes, parameterTypeDuration, parameterTypeListNumber:
+		return true
+	default:
+		return false
+	}
+}
+
+// parameterNumber returns the decimal value of f as an exact rational.
+// Numbers reach the provider at different precisions (float64 from the
+// configuration, 64 bits from environment variables), so they are compared by
+// their shortest decimal form rather than by their binary representation.
+func parameterNumber(f *big.Float) *big.Rat {
+	r, ok := new(big.Rat).SetString(f.Text('g', -1))
+	if !ok {
+		r, _ = f.Rat(nil)
+	}
+	return r
+}
+
+func formatParameterNumber(r *big.Rat) string {
+	if r.IsInt() {
+		return r.Num().String()
+	}
+	f, _ := r.Float64()
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// ratFloor returns the largest integer <= r.
+func ratFloor(r *big.Rat) *big.Int {
+	// Rat denominators are positive, so Euclidean division rounds down.
+	return new(big.Int).Div(r.Num(), r.Denom())
+}
+
+// parameterOptionValues returns the configured option values, which
+// validateParameterDefinition has already checked are set.
+func parameterOptionValues(options []parameterOptionModel) []string {
+	values := make([]string, 0, len(options))
+	for _, opt := range options {
+		values = append(values, opt.Value.ValueString())
+	}
+	return values
+}
+
+// values returns every validation attribute keyed by its schema name.
+func (v *parameterValidationModel) values() map[string]attr.Value {
+	return map[string]attr.Value{
+		"min":         v.Min,
+		"max":         v.Max,
+		"min_items":   v.MinItems,
+		"max_items":   v.MaxItems,
+		"regex":       v.Regex,
+		"min_length":  v.MinLength,
+		"max_length":  v.MaxLength,
+		"error":       v.Error,
+		"monotonic":   v.Monotonic,
+		"step":        v.Step,
+		"integer":     v.Integer,
+		"json_schema": v.JSONSchema,
+	}
+}
+
+// setAttributes returns the names of the configured (non-null) validation
+// attributes, sorted.
+func (v *parameterValidationModel) setAttributes() []string {
+	var set []string
+	for name, value := range v.values() {
+		if !value.IsNull() {
+			set = append(set, name)
+		}
+	}
+	slices.Sort(set)
+	return set
+}
+
+func isKnownValue(v attr.Value) bool {
+	return !v.IsNull() && isWhollyKnown(v)
+}
+
+// parameterRef identifies the parameter a diagnostic is about and renders
+// its values, hiding them when the parameter is sensitive.
+//
+// Unit is the unit of bytes and duration values: bare numbers in the
+// definition are read in it and rendered numbers are suffixed with it.
+// Path is the attribute the checked value came from: `value` at read time,
+// `default` when the default is checked at validate time.
+type parameterRef struct {
+	Name      string
+	Sensitive bool
+	Unit      string
+	Path      path.Path
+}
+
+const redactedParameterValue = "(sensitive value)"
+
+// quote renders a string value.
+func (p parameterRef) quote(value string) string {
+	if p.Sensitive {
+		return redactedParameterValue
+	}
+	return strconv.Quote(value)
+}
+
+// show renders a non-string value, or a value already formatted for display.
+func (p parameterRef) show(value string) string {
+	if p.Sensitive {
+		return redactedParameterValue
+	}
+	return value
+}
+
+// number renders a number in the parameter's unit. It is not redacted, so it
+// is only used for values from the parameter definition.
+func (p parameterRef) number(r *big.Rat) string {
+	if p.Unit == "" {
+		return formatParameterNumber(r)
+	}
+	return formatParameterNumber(r) + " " + p.Unit
+}
+
+// showNumber renders a number value in the parameter's unit.
+func (p parameterRef) showNumber(r *big.Rat) string {
+	return p.show(p.number(r))
+}
+
+func (p parameterRef) attributePath() path.Path {
+	if len(p.Path.Steps()) == 0 {
+		return path.Root("value")
+	}
+	return p.Path
+}
+
+// label names the checked value in diagnostic details.
+func (p parameterRef) label() string {
+	return p.attributePath().String()
+}
+
+// parameterValueError reports a value that violates the parameter's
+// constraints against the attribute it came from.
+func parameterValueError(p parameterRef, detail string) diag.Diagnostic {
+	return diag.NewAttributeErrorDiagnostic(
+		p.attributePath(),
+		"Invalid value",
+		fmt.Sprintf("parameter %q: %s", p.Name, detail),
+	)
+}
@@ -0,0 +1,106 @@
+// Copyright (c) WANIX Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// parametersFile holds parameter values supplied in bulk through
+// `MANIDAE_PARAMETERS_FILE` (or the provider `parameters_file` attribute).
+//
+// Values are kept as the raw strings the hashed environment variable would
+// carry: strings as-is, everything else JSON-encoded.
+type parametersFile struct {
+	Path   string
+	Values map[string]string
+}
+
+// loadParametersFile reads a JSON (`.json`) or YAML document whose top level
+// maps parameter names to values. Null entries are treated as unset.
+func loadParametersFile(path string) (*parametersFile, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var values map[string]string
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		values, err = decodeJSONParameters(content)
+	} else {
+		values, err = decodeYAMLParameters(content)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return &parametersFile{Path: path, Values: values}, nil
+}
+
+func decodeJSONParameters(content []byte) (map[string]string, error) {
+	var document map[string]json.RawMessage
+	if err := json.Unmarshal(content, &document); err != nil {
+		return nil, fmt.Errorf("expected a JSON object keyed by parameter name: %w", err)
+	}
+
+	values := make(map[string]string, len(document))
+	for name, raw := range document {
+		trimmed := bytes.TrimSpace(raw)
+		switch {
+		case bytes.Equal(trimmed, []byte("null")):
+			continue
+		case len(trimmed) > 0 && trimmed[0] == '"':
+			var s string
+			if err := json.Unmarshal(trimmed, &s); err != nil {
+				return nil, fmt.Errorf("parameter %q: %w", name, err)
+			}
+			values[name] = s
+		default:
+			var compact bytes.Buffer
+			if err := json.Compact(&compact, trimmed); err != nil {
+				return nil, fmt.Errorf("parameter %q: %w", name, err)
+			}
+			values[name] = compact.String()
+		}
+	}
+
+	return values, nil
+}
+
+func decodeYAMLParameters(content []byte) (map[string]string, error) {
+	var document map[string]yaml.Node
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return nil, fmt.Errorf("expected a YAML mapping keyed by parameter name: %w", err)
+	}
+
+	values := make(map[string]string, len(document))
+	for name, node := range document {
+		if node.Kind == yaml.ScalarNode {
+			if node.Tag == "!!null" {
+				continue
+			}
+			values[name] = node.Value
+			continue
+		}
+
+		var decoded any
+		if err := node.Decode(&decoded); err != nil {
+			return nil, fmt.Errorf("parameter %q: %w", name, err)
+		}
+		encoded, err := json.Marshal(decoded)
+		if err != nil {
+			return nil, fmt.Errorf("parameter %q: %w", name, err)
+		}
+		values[name] = string(encoded)
+	}
+
+	return values, nil
+}
@@ -0,0 +1,72 @@
+// Copyright (c) WANIX Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadParametersFile(t *testing.T) {
+	dir := t.TempDir()
+	want := map[string]string{
+		"instance_type":       "SA2.MEDIUM8",
+		"root_volume_size_gb": "50",
+		"enable_gpu":          "true",
+		"ports":               "[80,443]",
+	}
+
+	documents := map[string]string{
+		"parameters.json": `{
+  "instance_type": "SA2.MEDIUM8",
+  "root_volume_size_gb": 50,
+  "enable_gpu": true,
+  "ports": [80, 443],
+  "unset": null
+}`,
+		"parameters.yaml": `
+instance_type: SA2.MEDIUM8
+root_volume_size_gb: 50
+enable_gpu: true
+ports:
+  - 80
+  - 443
+unset: null
+`,
+	}
+
+	for name, document := range documents {
+		t.Run(name, func(t *testing.T) {
+			filePath := filepath.Join(dir, name)
+			if err := os.WriteFile(filePath, []byte(document), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := loadParametersFile(filePath)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if len(got.Values) != len(want) {
+				t.Fatalf("expected %d values, got %v", len(want), got.Values)
+			}
+			for key, value := range want {
+				if got.Values[key] != value {
+					t.Fatalf("expected %s = %q, got %q", key, value, got.Values[key])
+				}
+			}
+		})
+	}
+}
+
+func TestLoadParametersFile_RejectsNonObject(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "parameters.json")
+	if err := os.WriteFile(filePath, []byte(`["a"]`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := loadParametersFile(filePath); err == nil {
+		t.Fatalf("expected error, got none")
+	}
+}
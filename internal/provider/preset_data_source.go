@@ -0,0 +1,227 @@
+// Copyright (c) WANIX Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// presetEnvironmentVariable names the preset the platform selected for the
+// build.
+const presetEnvironmentVariable = "MANIDAE_PRESET"
+
+// Ensure presetDataSource satisfies the data source interfaces.
+var _ datasource.DataSourceWithConfigure = &presetDataSource{}
+
+type presetDataSource struct {
+	providerData *manidaeProviderData
+}
+
+type presetDataSourceModel struct {
+	ID          types.String  `tfsdk:"id"`
+	Name        types.String  `tfsdk:"name"`
+	DisplayName types.String  `tfsdk:"display_name"`
+	Description types.String  `tfsdk:"description"`
+	Parameters  types.Dynamic `tfsdk:"parameters"`
+	Selected    types.Bool    `tfsdk:"selected"`
+}
+
+func NewPresetDataSource() datasource.DataSource {
+	return &presetDataSource{}
+}
+
+func (d *presetDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_preset"
+}
+
+func (d *presetDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Declares a named set of parameter values. When `MANIDAE_PRESET` names the preset, parameters that list it in `presets` resolve to its values unless their environment variable or the parameters file supplies one.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Internal identifier (same as `name`). Reference it from a parameter's `presets` so the preset is read first.",
+			},
+			"name": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Preset name, matched against `MANIDAE_PRESET`.",
+			},
+			"display_name": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Human-friendly display name.",
+			},
+			"description": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Human-friendly description.",
+			},
+			"parameters": schema.DynamicAttribute{
+				Required:            true,
+				MarkdownDescription: "Object mapping parameter names to values. Values are read like the parameter's environment variable: strings as-is, lists and objects as their JSON encoding, and units are allowed for `bytes` and `duration`.",
+			},
+			"selected": schema.BoolAttribute{
+				Computed:            true,
+				MarkdownDescription: "Whether `MANIDAE_PRESET`, or the instance context's `preset` when the context file or the Manidae API is in use, selects this preset.",
+			},
+		},
+	}
+}
+
+func (d *presetDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*manidaeProviderData)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected Data Source Configure Type", fmt.Sprintf("Expected *manidaeProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData))
+		return
+	}
+
+	d.providerData = providerData
+}
+
+func (d *presetDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data presetDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	name := strings.TrimSpace(data.Name.ValueString())
+	if name == "" {
+		resp.Diagnostics.AddAttributeError(path.Root("name"), "Invalid name", "`name` must not be empty")
+		return
+	}
+
+	values, valuesDiags := presetParameterValues(data.Parameters)
+	resp.Diagnostics.Append(valuesDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if d.providerData != nil {
+		if err := d.providerData.presets.register(name, values); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("name"), "Duplicate preset", err.Error())
+			return
+		}
+	}
+
+	data.ID = types.StringValue(name)
+	var instanceContext *contextFile
+	if d.providerData != nil {
+		var contextDiags diag.Diagnostics
+		instanceContext, contextDiags = d.providerData.instanceContext(ctx)
+		resp.Diagnostics.Append(contextDiags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	selected, _ := selectedPreset(instanceContext)
+	data.Selected = types.BoolValue(selected == name)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// selectedPreset returns the preset named by `MANIDAE_PRESET`, or by the
+// `preset` field of the instance context when one is in use, along with a
+// description of where it came from. The name is "" when no preset was
+// selected.
+func selectedPreset(context *contextFile) (string, string) {
+	if context != nil {
+		return strings.TrimSpace(context.Payload.Preset), fmt.Sprintf("`preset` in instance context %q", context.Path)
+	}
+	return strings.TrimSpace(os.Getenv(presetEnvironmentVariable)), presetEnvironmentVariable
+}
+
+// presetParameterValues converts `parameters` into the raw strings the
+// parameters' environment variables would carry, as the parameters file
+// does. Null entries are treated as unset.
+func presetParameterValues(parameters types.Dynamic) (map[string]string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	var attributes map[string]attr.Value
+	switch v := parameters.UnderlyingValue().(type) {
+	case types.Object:
+		attributes = v.Attributes()
+	case types.Map:
+		attributes = v.Elements()
+	default:
+		diags.AddAttributeError(path.Root("parameters"), "Invalid parameters", "`parameters` must be an object mapping parameter names to values")
+		return nil, diags
+	}
+
+	values := make(map[string]string, len(attributes))
+	for name, value := range attributes {
+		decoded, err := parameterValueToJSON(value)
+		if err != nil {
+			diags.AddAttributeError(path.Root("parameters").AtMapKey(name), "Invalid parameters", fmt.Sprintf("value for parameter %q %s", name, err))
+			continue
+		}
+
+		switch v := decoded.(type) {
+		case nil:
+		case string:
+			values[name] = v
+		default:
+			encoded, err := json.Marshal(v)
+			if err != nil {
+				diags.AddAttributeError(path.Root("parameters").AtMapKey(name), "Invalid parameters", fmt.Sprintf("value for parameter %q cannot be encoded: %s", name, err))
+				continue
+			}
+			values[name] = string(encoded)
+		}
+	}
+
+	return values, diags
+}
+
+// presetRegistry collects the values of every preset read by the provider,
+// so parameters can resolve values from the selected one. Data sources are
+// read concurrently.
+type presetRegistry struct {
+	mu      sync.Mutex
+	presets map[string]map[string]string
+}
+
+// register records a preset. Reading the same preset again is allowed, but
+// two presets may not share a name with different values.
+func (r *presetRegistry) register(name string, values map[string]string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, ok := r.presets[name]; ok {
+		if !maps.Equal(existing, values) {
+			return fmt.Errorf("preset %q is declared more than once with different parameters", name)
+		}
+		return nil
+	}
+
+	if r.presets == nil {
+		r.presets = make(map[string]map[string]string)
+	}
+	r.presets[name] = values
+	return nil
+}
+
+func (r *presetRegistry) lookup(name string) (map[string]string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	values, ok := r.presets[name]
+	return values, ok
+}
@@ -0,0 +1,130 @@
+// Copyright (c) WANIX Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestPresetParameterValues(t *testing.T) {
+	parameters := types.DynamicValue(types.ObjectValueMust(
+		map[string]attr.Type{
+			"instance_type": types.StringType,
+			"cpu_count":     types.NumberType,
+			"ide_plugins":   types.TupleType{ElemTypes: []attr.Type{types.StringType, types.StringType}},
+			"region":        types.DynamicType,
+		},
+		map[string]attr.Value{
+			"instance_type": types.StringValue("SA2.LARGE8"),
+			"cpu_count":     types.NumberValue(big.NewFloat(8)),
+			"ide_plugins":   types.TupleValueMust([]attr.Type{types.StringType, types.StringType}, []attr.Value{types.StringValue("go"), types.StringValue("rust")}),
+			"region":        types.DynamicNull(),
+		},
+	))
+
+	values, diags := presetParameterValues(parameters)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	expected := map[string]string{
+		"instance_type": "SA2.LARGE8",
+		"cpu_count":     "8",
+		"ide_plugins":   `["go","rust"]`,
+	}
+	if len(values) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, values)
+	}
+	for name, want := range expected {
+		if values[name] != want {
+			t.Fatalf("expected %s = %q, got %q", name, want, values[name])
+		}
+	}
+
+	if _, diags := presetParameterValues(types.DynamicValue(types.StringValue("small"))); !diags.HasError() {
+		t.Fatalf("expected error for non-object parameters")
+	}
+}
+
+func TestPresetRegistry(t *testing.T) {
+	var registry presetRegistry
+
+	if err := registry.register("small", map[string]string{"cpu_count": "2"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := registry.register("small", map[string]string{"cpu_count": "2"}); err != nil {
+		t.Fatalf("re-reading a preset should be allowed: %s", err)
+	}
+	if err := registry.register("small", map[string]string{"cpu_count": "4"}); err == nil {
+		t.Fatalf("expected error for conflicting preset")
+	}
+
+	values, ok := registry.lookup("small")
+	if !ok || values["cpu_count"] != "2" {
+		t.Fatalf("unexpected lookup result %v, %t", values, ok)
+	}
+	if _, ok := registry.lookup("large"); ok {
+		t.Fatalf("expected no large preset")
+	}
+}
+
+func TestResolveParameterValue_PresetPrecedence(t *testing.T) {
+	envKey := ParameterEnvironmentVariable("cpu_count")
+	sources := parameterSources{
+		Name:         "cpu_count",
+		EnvKey:       envKey,
+		Preset:       "large",
+		PresetValues: map[string]string{"cpu_count": "16"},
+	}
+	defaultValue := types.DynamicValue(types.NumberValue(big.NewFloat(4)))
+
+	got, diags := resolveParameterValue(parameterTypeNumber, "", sources, defaultValue)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %#v", diags)
+	}
+	if got.Source != parameterSourcePreset || got.Raw != "16" {
+		t.Fatalf("expected preset value, got %q from %s", got.Raw, got.Source)
+	}
+
+	sources.File = &parametersFile{Path: "parameters.json", Values: map[string]string{"cpu_count": "8"}}
+
+	got, diags = resolveParameterValue(parameterTypeNumber, "", sources, defaultValue)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %#v", diags)
+	}
+	if got.Source != parameterSourceFile {
+		t.Fatalf("expected parameters file to take precedence over the preset, got %s", got.Source)
+	}
+
+	t.Setenv(envKey, "2")
+
+	got, diags = resolveParameterValue(parameterTypeNumber, "", sources, defaultValue)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %#v", diags)
+	}
+	if got.Source != parameterSourceEnvironment {
+		t.Fatalf("expected environment variable to take precedence over the preset, got %s", got.Source)
+	}
+}
+
+func TestSelectedPreset_ContextReplacesEnvironment(t *testing.T) {
+	t.Setenv(presetEnvironmentVariable, "large")
+
+	if got, _ := selectedPreset(nil); got != "large" {
+		t.Fatalf("expected %q, got %q", "large", got)
+	}
+
+	context := &contextFile{Path: "context.json"}
+	if got, _ := selectedPreset(context); got != "" {
+		t.Fatalf("expected no preset, got %q", got)
+	}
+	context.Payload.Preset = " small "
+	if got, _ := selectedPreset(context); got != "small" {
+		t.Fatalf("expected %q, got %q", "small", got)
+	}
+}
@@ -5,9 +5,15 @@ package provider
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -28,7 +34,31 @@ type ManidaeProvider struct {
 
 // ManidaeProviderModel describes the provider data model.
 type ManidaeProviderModel struct {
-	Endpoint types.String `tfsdk:"endpoint"`
+	Endpoint                types.String `tfsdk:"endpoint"`
+	Token                   types.String `tfsdk:"token"`
+	CABundleFile            types.String `tfsdk:"ca_bundle_file"`
+	Timeout                 types.String `tfsdk:"timeout"`
+	MaxRetries              types.Int64  `tfsdk:"max_retries"`
+	ParametersFile          types.String `tfsdk:"parameters_file"`
+	InstanceStates          types.Map    `tfsdk:"instance_states"`
+	ContextFile             types.String `tfsdk:"context_file"`
+	ContextHMACSecret       types.String `tfsdk:"context_hmac_secret"`
+	ContextEd25519PublicKey types.String `tfsdk:"context_ed25519_public_key"`
+	ContextEnvFallback      types.Bool   `tfsdk:"context_env_fallback"`
+}
+
+// manidaeProviderData is shared with data sources through
+// `DataSourceData`.
+type manidaeProviderData struct {
+	context            *contextFile
+	client             *manidaeClient
+	parametersFile     *parametersFile
+	instanceFootprints instanceFootprints
+	presets            presetRegistry
+	parameters         parameterRegistry
+
+	remoteContextMu sync.Mutex
+	remoteContext   *contextFile
 }
 
 func (p *ManidaeProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -40,7 +70,50 @@ func (p *ManidaeProvider) Schema(ctx context.Context, req provider.SchemaRequest
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
 			"endpoint": schema.StringAttribute{
-				MarkdownDescription: "Example provider attribute",
+				MarkdownDescription: "Base URL of the Manidae API, e.g. `https://manidae.example.com`. When set, every data source reads the context of `MANIDAE_INSTANCE_ID`, fetched once from the control plane, instead of environment variables, unless a context file is in use.",
+				Optional:            true,
+			},
+			"token": schema.StringAttribute{
+				MarkdownDescription: "API token sent as a bearer token. Defaults to `MANIDAE_TOKEN`. Required when `endpoint` is set.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"ca_bundle_file": schema.StringAttribute{
+				MarkdownDescription: "Path to a PEM file with the CA certificates trusted for `endpoint`, replacing the system roots. Defaults to `MANIDAE_CA_BUNDLE_FILE`.",
+				Optional:            true,
+			},
+			"timeout": schema.StringAttribute{
+				MarkdownDescription: "Timeout of each API request attempt, as a Go duration such as `30s`. Defaults to `30s`.",
+				Optional:            true,
+			},
+			"max_retries": schema.Int64Attribute{
+				MarkdownDescription: "How many times an API request failing with a network error, `429` or a `5xx` response is retried, with exponential backoff. Defaults to `3`.",
+				Optional:            true,
+			},
+			"parameters_file": schema.StringAttribute{
+				MarkdownDescription: "Path to a JSON (`.json`) or YAML document mapping parameter names to values. Defaults to `MANIDAE_PARAMETERS_FILE`. A parameter's hashed environment variable takes precedence over the file.",
+				Optional:            true,
+			},
+			"instance_states": schema.MapAttribute{
+				MarkdownDescription: "Resource footprint per `MANIDAE_INSTANCE_STATE`, as a map of state to a map of resource class to count. Entries replace the built-in footprint of a state (`on`, `off`, `suspended`, `hibernated`) or declare a new state; `manidae_instance` exposes the footprint of the current state as `counts`.",
+				Optional:            true,
+				ElementType:         types.MapType{ElemType: types.Int64Type},
+			},
+			"context_file": schema.StringAttribute{
+				MarkdownDescription: "Path to a signed JSON context file holding the instance, action, owner and parameter values. Defaults to `MANIDAE_CONTEXT_FILE`. When set, `manidae_instance`, `manidae_owner` and `manidae_parameter` read it instead of environment variables, and its signature must verify against `context_hmac_secret` or `context_ed25519_public_key`.",
+				Optional:            true,
+			},
+			"context_hmac_secret": schema.StringAttribute{
+				MarkdownDescription: "Shared secret verifying an `hmac-sha256` context file signature. Conflicts with `context_ed25519_public_key`.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"context_ed25519_public_key": schema.StringAttribute{
+				MarkdownDescription: "Public key verifying an `ed25519` context file signature, PEM-encoded or as the base64-encoded 32-byte key. Conflicts with `context_hmac_secret`.",
+				Optional:            true,
+			},
+			"context_env_fallback": schema.BoolAttribute{
+				MarkdownDescription: "Whether data sources may read environment variables when a context key is configured but no context file is present. Defaults to `false`, which makes a missing context file an error.",
 				Optional:            true,
 			},
 		},
@@ -55,6 +128,127 @@ func (p *ManidaeProvider) Configure(ctx context.Context, req provider.ConfigureR
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	if data.ParametersFile.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(path.Root("parameters_file"), "Invalid parameters file", "`parameters_file` must be known")
+		return
+	}
+
+	if data.InstanceStates.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(path.Root("instance_states"), "Invalid instance states", "`instance_states` must be known")
+		return
+	}
+
+	if data.ContextFile.IsUnknown() || data.ContextHMACSecret.IsUnknown() || data.ContextEd25519PublicKey.IsUnknown() || data.ContextEnvFallback.IsUnknown() {
+		resp.Diagnostics.AddError("Invalid context file", "`context_file`, `context_hmac_secret`, `context_ed25519_public_key` and `context_env_fallback` must be known")
+		return
+	}
+
+	if data.Endpoint.IsUnknown() || data.Token.IsUnknown() || data.CABundleFile.IsUnknown() || data.Timeout.IsUnknown() || data.MaxRetries.IsUnknown() {
+		resp.Diagnostics.AddError("Invalid API configuration", "`endpoint`, `token`, `ca_bundle_file`, `timeout` and `max_retries` must be known")
+		return
+	}
+
+	providerData := &manidaeProviderData{}
+
+	client, clientDiags := configureManidaeClient(data, p.version)
+	resp.Diagnostics.Append(clientDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	providerData.client = client
+
+	contextFilePath := os.Getenv("MANIDAE_CONTEXT_FILE")
+	if !data.ContextFile.IsNull() {
+		contextFilePath = data.ContextFile.ValueString()
+	}
+	instanceContext, contextDiags := configureContextFile(contextFilePath, data.ContextHMACSecret, data.ContextEd25519PublicKey, data.ContextEnvFallback.ValueBool())
+	resp.Diagnostics.Append(contextDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	providerData.context = instanceContext
+
+	var instanceStates map[string]map[string]int64
+	if !data.InstanceStates.IsNull() {
+		resp.Diagnostics.Append(data.InstanceStates.ElementsAs(ctx, &instanceStates, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	footprints, footprintDiags := mergeInstanceFootprints(instanceStates)
+	resp.Diagnostics.Append(footprintDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	providerData.instanceFootprints = footprints
+
+	parametersFilePath := os.Getenv("MANIDAE_PARAMETERS_FILE")
+	if !data.ParametersFile.IsNull() {
+		parametersFilePath = data.ParametersFile.ValueString()
+	}
+	if parametersFilePath != "" {
+		file, err := loadParametersFile(parametersFilePath)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("parameters_file"), "Invalid parameters file", err.Error())
+			return
+		}
+		providerData.parametersFile = file
+	}
+
+	resp.DataSourceData = providerData
+}
+
+// configureManidaeClient builds the API client, or returns nil when no
+// endpoint is configured.
+func configureManidaeClient(data ManidaeProviderModel, version string) (*manidaeClient, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if data.Endpoint.IsNull() || data.Endpoint.ValueString() == "" {
+		return nil, diags
+	}
+
+	config := manidaeClientConfig{
+		Endpoint:     data.Endpoint.ValueString(),
+		Token:        os.Getenv("MANIDAE_TOKEN"),
+		CABundleFile: os.Getenv("MANIDAE_CA_BUNDLE_FILE"),
+		Timeout:      defaultAPITimeout,
+		MaxRetries:   defaultAPIMaxRetries,
+		UserAgent:    fmt.Sprintf("terraform-provider-manidae/%s", version),
+	}
+	if !data.Token.IsNull() {
+		config.Token = data.Token.ValueString()
+	}
+	if !data.CABundleFile.IsNull() {
+		config.CABundleFile = data.CABundleFile.ValueString()
+	}
+	if !data.Timeout.IsNull() {
+		timeout, err := time.ParseDuration(data.Timeout.ValueString())
+		if err != nil || timeout <= 0 {
+			diags.AddAttributeError(path.Root("timeout"), "Invalid API configuration", fmt.Sprintf("`timeout` must be a positive duration such as \"30s\", got %q", data.Timeout.ValueString()))
+			return nil, diags
+		}
+		config.Timeout = timeout
+	}
+	if !data.MaxRetries.IsNull() {
+		if data.MaxRetries.ValueInt64() < 0 {
+			diags.AddAttributeError(path.Root("max_retries"), "Invalid API configuration", "`max_retries` must not be negative")
+			return nil, diags
+		}
+		config.MaxRetries = int(data.MaxRetries.ValueInt64())
+	}
+
+	if config.Token == "" {
+		diags.AddAttributeError(path.Root("token"), "Missing API token", "`endpoint` is set, so `token` or `MANIDAE_TOKEN` must be set too")
+		return nil, diags
+	}
+
+	client, err := newManidaeClient(config)
+	if err != nil {
+		diags.AddAttributeError(path.Root("endpoint"), "Invalid API configuration", err.Error())
+		return nil, diags
+	}
+	return client, diags
 }
 
 func (p *ManidaeProvider) Resources(ctx context.Context) []func() resource.Resource {
@@ -64,7 +258,10 @@ func (p *ManidaeProvider) Resources(ctx context.Context) []func() resource.Resou
 func (p *ManidaeProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		NewParameterDataSource,
+		NewSensitiveParameterDataSource,
 		NewInstanceDataSource,
+		NewPresetDataSource,
+		NewOwnerDataSource,
 	}
 }
 
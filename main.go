@@ -5,8 +5,12 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
+	"fmt"
+	"io"
 	"log"
+	"os"
 
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/indexyz/terraform-provider-manidae/internal/provider"
@@ -22,6 +26,13 @@ var (
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "schema-export" {
+		if err := runSchemaExport(os.Args[2:], os.Stdout); err != nil {
+			log.Fatal(err.Error())
+		}
+		return
+	}
+
 	var debug bool
 
 	flag.BoolVar(&debug, "debug", false, "set to true to run the provider with support for debuggers like delve")
@@ -38,3 +49,38 @@ func main() {
 		log.Fatal(err.Error())
 	}
 }
+
+// runSchemaExport implements `terraform-provider-manidae schema-export [dir]`,
+// printing every `manidae_parameter` and `manidae_sensitive_parameter`
+// declared in the template as JSON.
+func runSchemaExport(args []string, w io.Writer) error {
+	flags := flag.NewFlagSet("schema-export", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s schema-export [template-dir]\n", os.Args[0])
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 1 {
+		flags.Usage()
+		return fmt.Errorf("schema-export accepts at most one template directory")
+	}
+
+	dir := "."
+	if flags.NArg() == 1 {
+		dir = flags.Arg(0)
+	}
+
+	parameters, err := provider.ExportParameterSchemas(dir)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(struct {
+		Parameters []provider.ParameterSchema `json:"parameters"`
+	}{
+		Parameters: parameters,
+	})
+}